@@ -0,0 +1,60 @@
+package configs
+
+import "time"
+
+// AlertConfig checks a single stored value after its feed updates
+// and sends a message to each notifier when the condition is met.
+type AlertConfig struct {
+	Name           string    `yaml:"name"`
+	Value          string    `yaml:"value"`
+	When           Condition `yaml:"when"`
+	Message        string    `yaml:"message,omitempty"`
+	ResendEvery    string    `yaml:"resendEvery,omitempty"`
+	NotifyRecovery bool      `yaml:"notifyRecovery,omitempty"`
+	Notify         []string  `yaml:"notify"`
+
+	// ResendEveryDuration is set from resendEvery when the config is loaded
+	ResendEveryDuration time.Duration `yaml:"-"`
+}
+
+// Feed returns the feed name half of the alert value.
+func (a *AlertConfig) Feed() string {
+	feed, _ := splitValue(a.Value)
+	return feed
+}
+
+// Store returns the store name half of the alert value.
+func (a *AlertConfig) Store() string {
+	_, store := splitValue(a.Value)
+	return store
+}
+
+// NotifierConfig is a destination for alert messages.
+// Supported types are webhook, slack, ntfy and email.
+type NotifierConfig struct {
+	Name    string            `yaml:"name"`
+	Type    string            `yaml:"type"`
+	Url     string            `yaml:"url,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// ntfy only
+	Priority string `yaml:"priority,omitempty"`
+
+	// email only
+	Host     string   `yaml:"host,omitempty"`
+	Port     int      `yaml:"port,omitempty"`
+	Username string   `yaml:"username,omitempty"`
+	Password string   `yaml:"password,omitempty"`
+	From     string   `yaml:"from,omitempty"`
+	To       []string `yaml:"to,omitempty"`
+}
+
+func (c *Config) AlertsByFeed(name string) []AlertConfig {
+	var alerts []AlertConfig
+	for _, a := range c.Alerts {
+		if a.Feed() == name {
+			alerts = append(alerts, a)
+		}
+	}
+	return alerts
+}
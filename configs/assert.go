@@ -0,0 +1,41 @@
+package configs
+
+import (
+	"fmt"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// CompileSchema compiles the yaml schema as a JSON Schema
+func (a *FeedAssert) CompileSchema() (*gojsonschema.Schema, error) {
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(jsonCompatible(a.Schema)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return schema, nil
+}
+
+// jsonCompatible converts yaml maps with interface keys to string keys
+func jsonCompatible(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for k, inner := range v {
+			converted[fmt.Sprint(k)] = jsonCompatible(inner)
+		}
+		return converted
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for k, inner := range v {
+			converted[k] = jsonCompatible(inner)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, inner := range v {
+			converted[i] = jsonCompatible(inner)
+		}
+		return converted
+	}
+	return value
+}
@@ -0,0 +1,35 @@
+package configs
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestCompileSchema(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		schema string
+		fails  bool
+	}{
+		{name: "object", schema: "{type: object, required: [items], properties: {items: {type: array}}}"},
+		{name: "numeric keys", schema: "{type: array, items: {enum: [1, 2]}}"},
+		{name: "unknown type", schema: "{type: thing}", fails: true},
+		{name: "bad required", schema: "{type: object, required: items}", fails: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var assert FeedAssert
+			if err := yaml.Unmarshal([]byte("schema: "+tc.schema), &assert); err != nil {
+				t.Fatalf("unable to parse schema: %v", err)
+			}
+
+			_, err := assert.CompileSchema()
+			if tc.fails && err == nil {
+				t.Fatal("expected an error")
+			}
+			if !tc.fails && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package configs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// operators are ordered so two character operators are matched first
+var operators = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// Condition is a simple comparison against a single value.
+// It is written in yaml as a string like `value > 90` or `value == "down"`.
+type Condition struct {
+	Operator string
+	Operand  interface{}
+}
+
+func ParseCondition(expr string) (Condition, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "value") {
+		return Condition{}, fmt.Errorf("condition '%v' must start with 'value'", expr)
+	}
+
+	rest := strings.TrimSpace(expr[5:])
+	for _, op := range operators {
+		if !strings.HasPrefix(rest, op) {
+			continue
+		}
+
+		operand := strings.TrimSpace(rest[len(op):])
+		if operand == "" {
+			return Condition{}, fmt.Errorf("condition '%v' is missing a right hand side", expr)
+		}
+
+		return Condition{
+			Operator: op,
+			Operand:  parseOperand(operand),
+		}, nil
+	}
+
+	return Condition{}, fmt.Errorf("condition '%v' has no valid operator", expr)
+}
+
+func parseOperand(operand string) interface{} {
+	if unquoted, err := strconv.Unquote(operand); err == nil {
+		return unquoted
+	}
+
+	if f, err := strconv.ParseFloat(operand, 64); err == nil {
+		return f
+	}
+
+	if b, err := strconv.ParseBool(operand); err == nil {
+		return b
+	}
+
+	return operand
+}
+
+func (c *Condition) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var expr string
+	if err := unmarshal(&expr); err != nil {
+		return err
+	}
+
+	cond, err := ParseCondition(expr)
+	if err != nil {
+		return err
+	}
+
+	*c = cond
+	return nil
+}
+
+func (c Condition) String() string {
+	if s, ok := c.Operand.(string); ok {
+		return fmt.Sprintf("value %v %q", c.Operator, s)
+	}
+	return fmt.Sprintf("value %v %v", c.Operator, c.Operand)
+}
+
+// Matches evaluates the condition against a stored value.
+// Values are usually strings from the store so numbers are parsed when
+// the operand is numeric.
+func (c Condition) Matches(value interface{}) bool {
+	switch operand := c.Operand.(type) {
+	case float64:
+		f, ok := ToFloat(value)
+		if !ok {
+			return c.Operator == "!="
+		}
+		return compare(c.Operator, f-operand)
+	case bool:
+		b, ok := toBool(value)
+		if !ok {
+			return c.Operator == "!="
+		}
+		switch c.Operator {
+		case "==":
+			return b == operand
+		case "!=":
+			return b != operand
+		}
+		return false
+	case string:
+		return compare(c.Operator, float64(strings.Compare(fmt.Sprint(value), operand)))
+	}
+
+	return false
+}
+
+func compare(operator string, diff float64) bool {
+	switch operator {
+	case ">":
+		return diff > 0
+	case ">=":
+		return diff >= 0
+	case "<":
+		return diff < 0
+	case "<=":
+		return diff <= 0
+	case "==":
+		return diff == 0
+	case "!=":
+		return diff != 0
+	}
+	return false
+}
+
+// ToFloat converts numbers and numeric strings to a float
+func ToFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func toBool(value interface{}) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		return b, err == nil
+	}
+	return false, false
+}
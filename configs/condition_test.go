@@ -0,0 +1,88 @@
+package configs
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestParseCondition(t *testing.T) {
+	for _, tc := range []struct {
+		expr     string
+		operator string
+		operand  interface{}
+		fails    bool
+	}{
+		{expr: "value > 90", operator: ">", operand: 90.0},
+		{expr: "value>=1.5", operator: ">=", operand: 1.5},
+		{expr: " value <= -2 ", operator: "<=", operand: -2.0},
+		{expr: "value < 0", operator: "<", operand: 0.0},
+		{expr: `value == "down"`, operator: "==", operand: "down"},
+		{expr: "value != true", operator: "!=", operand: true},
+		{expr: "value == up", operator: "==", operand: "up"},
+		{expr: "count > 1", fails: true},
+		{expr: "value >", fails: true},
+		{expr: "value ~ 1", fails: true},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			cond, err := ParseCondition(tc.expr)
+			if tc.fails {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", cond)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cond.Operator != tc.operator || cond.Operand != tc.operand {
+				t.Errorf("expected %v %v, got %v %v", tc.operator, tc.operand, cond.Operator, cond.Operand)
+			}
+		})
+	}
+}
+
+func TestConditionMatches(t *testing.T) {
+	for _, tc := range []struct {
+		expr     string
+		value    interface{}
+		expected bool
+	}{
+		{expr: "value > 90", value: 95.0, expected: true},
+		{expr: "value > 90", value: "95", expected: true},
+		{expr: "value > 90", value: 90, expected: false},
+		{expr: "value >= 90", value: int64(90), expected: true},
+		{expr: "value > 90", value: "high", expected: false},
+		{expr: "value != 90", value: "high", expected: true},
+		{expr: "value == true", value: true, expected: true},
+		{expr: "value == true", value: "false", expected: false},
+		{expr: "value != true", value: nil, expected: true},
+		{expr: `value == "down"`, value: "down", expected: true},
+		{expr: `value != "down"`, value: "up", expected: true},
+		{expr: `value < "b"`, value: "a", expected: true},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			cond, err := ParseCondition(tc.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := cond.Matches(tc.value); got != tc.expected {
+				t.Errorf("expected %v for %#v, got %v", tc.expected, tc.value, got)
+			}
+		})
+	}
+}
+
+func TestConditionUnmarshalYAML(t *testing.T) {
+	var rule ContentRule
+	if err := yaml.Unmarshal([]byte("when: value > 3\nstyle: warning\n"), &rule); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule.When.String() != "value > 3" {
+		t.Errorf("expected 'value > 3', got '%v'", rule.When)
+	}
+
+	if err := yaml.Unmarshal([]byte("when: value\n"), &rule); err == nil {
+		t.Error("expected an error for a condition without an operator")
+	}
+}
@@ -1,9 +1,14 @@
 package configs
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
 	"github.com/caarlos0/env/v6"
 	"gopkg.in/yaml.v2"
-	"time"
 )
 
 func NewConfig(configYml []byte) (*Config, error) {
@@ -18,39 +23,446 @@ func NewConfig(configYml []byte) (*Config, error) {
 		return &c, err
 	}
 
+	if err := c.validate(); err != nil {
+		return &c, err
+	}
+
 	return &c, nil
 }
 
 type Config struct {
-	Feeds     []FeedConfig `yaml:"feeds"`
-	Env       EnvConfig
-	Dashboard Dashboard `yaml:"dashboard"`
+	Feeds      []FeedConfig `yaml:"feeds"`
+	Env        EnvConfig
+	Dashboard  Dashboard            `yaml:"dashboard"`
+	Dashboards map[string]Dashboard `yaml:"dashboards,omitempty"`
+	Kiosk      *KioskConfig         `yaml:"kiosk,omitempty"`
+	Themes     map[string]UserTheme `yaml:"themes,omitempty"`
+	Alerts     []AlertConfig        `yaml:"alerts,omitempty"`
+	Notifiers  []NotifierConfig     `yaml:"notifiers,omitempty"`
+	RateLimits []RateLimitConfig    `yaml:"rateLimits,omitempty"`
+}
+
+// RateLimitConfig is a token bucket for all requests to a host,
+// allowing requests per duration with bursts up to burst, default requests.
+type RateLimitConfig struct {
+	Host     string `yaml:"host"`
+	Requests int    `yaml:"requests"`
+	Per      string `yaml:"per"`
+	Burst    int    `yaml:"burst,omitempty"`
+
+	// PerDuration is the parsed per, always positive once validated
+	PerDuration time.Duration `yaml:"-"`
+}
+
+func (c *Config) validate() error {
+	if err := c.expandFeeds(); err != nil {
+		return err
+	}
+
+	if err := c.sortFeeds(); err != nil {
+		return err
+	}
+
+	for i := range c.RateLimits {
+		r := &c.RateLimits[i]
+		if r.Host == "" || r.Requests <= 0 {
+			return errors.New("rate limits require a host and requests")
+		}
+
+		var err error
+		if r.PerDuration, err = time.ParseDuration(r.Per); err != nil {
+			return fmt.Errorf("rate limit for '%v' has invalid per: %w", r.Host, err)
+		}
+		if r.PerDuration <= 0 {
+			return fmt.Errorf("rate limit for '%v' requires a positive per", r.Host)
+		}
+	}
+
+	for i := range c.Feeds {
+		f := &c.Feeds[i]
+		if err := f.validate(); err != nil {
+			return fmt.Errorf("invalid feed '%v': %w", f.Name, err)
+		}
+	}
+
+	if err := c.validateDashboards(); err != nil {
+		return err
+	}
+
+	for i := range c.Alerts {
+		a := &c.Alerts[i]
+		feed := c.FeedByName(a.Feed())
+		if feed == nil || feed.StoreByName(a.Store()) == nil {
+			return fmt.Errorf("alert '%v' references unknown value '%v'", a.Name, a.Value)
+		}
+
+		if a.ResendEvery != "" {
+			var err error
+			if a.ResendEveryDuration, err = time.ParseDuration(a.ResendEvery); err != nil {
+				return fmt.Errorf("alert '%v' has invalid resendEvery: %w", a.Name, err)
+			}
+		}
+
+		for _, n := range a.Notify {
+			if c.NotifierByName(n) == nil {
+				return fmt.Errorf("alert '%v' references unknown notifier '%v'", a.Name, n)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateLayers checks layers fit in their grid and recursively checks
+// any child layers against the grid of their parent.
+func (c *Config) validateLayers(grid Grid, layers []Layer) error {
+	if err := grid.validate(); err != nil {
+		return err
+	}
+
+	for i := range layers {
+		l := &layers[i]
+		if err := grid.validateLayer(*l); err != nil {
+			return fmt.Errorf("invalid layer '%v': %w", l.Name, err)
+		}
+
+		if l.ForEach != "" && len(c.FeedsByGroup(l.ForEach)) == 0 {
+			return fmt.Errorf("layer '%v' references unknown forEach feed '%v'", l.Name, l.ForEach)
+		}
+
+		// forEach layers are templated so their references are only known later
+		if l.Updated != "" && !strings.Contains(l.Updated, "{{") {
+			name, store := splitValue(l.Updated)
+			feed := c.FeedByName(name)
+			if feed == nil || (store != "" && feed.StoreByName(store) == nil) {
+				return fmt.Errorf("layer '%v' references unknown updated value '%v'", l.Name, l.Updated)
+			}
+		}
+
+		for j := range l.Contents {
+			if err := l.Contents[j].validate(); err != nil {
+				return fmt.Errorf("invalid content in layer '%v': %w", l.Name, err)
+			}
+		}
+
+		if err := c.validateLayers(l.Grid, l.Layers); err != nil {
+			return fmt.Errorf("invalid child of layer '%v': %w", l.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (f *FeedConfig) validate() error {
+	switch strings.ToLower(f.Type) {
+	case "", "http":
+	case "prometheus":
+		if f.Prometheus == nil || f.Prometheus.Url == "" || f.Prometheus.Query == "" {
+			return errors.New("prometheus feeds require a url and query")
+		}
+		if r := f.Prometheus.Range; r != nil {
+			var err error
+			if r.SinceDuration, err = time.ParseDuration(r.Since); err != nil {
+				return fmt.Errorf("invalid prometheus range since: %w", err)
+			}
+			if r.StepDuration, err = time.ParseDuration(r.Step); err != nil {
+				return fmt.Errorf("invalid prometheus range step: %w", err)
+			}
+		}
+	case "push":
+		if f.Schedule.Every != "" {
+			return errors.New("push feeds can not have a schedule")
+		}
+		if f.Push == nil || (f.Push.Secret == "" && !f.Push.Insecure) {
+			return errors.New("push feeds require a secret or insecure to accept unsigned pushes")
+		}
+	case "sql":
+		if f.SQL == nil || f.SQL.DSN == "" || f.SQL.Query == "" {
+			return errors.New("sql feeds require a dsn and query")
+		}
+		switch f.SQL.Driver {
+		case "postgres", "mysql", "sqlite3":
+		default:
+			return fmt.Errorf("sql driver '%v' not found", f.SQL.Driver)
+		}
+		if f.SQL.Timeout != "" {
+			var err error
+			if f.SQL.TimeoutDuration, err = time.ParseDuration(f.SQL.Timeout); err != nil {
+				return fmt.Errorf("invalid sql timeout: %w", err)
+			}
+		}
+	case "redis":
+		if f.Redis == nil || len(f.Redis.Keys) == 0 {
+			return errors.New("redis feeds require keys")
+		}
+		for _, k := range f.Redis.Keys {
+			switch k.Type {
+			case "", "string", "hash", "list", "zrange", "llen", "scard", "zcard", "xlen":
+			default:
+				return fmt.Errorf("redis key type '%v' not found", k.Type)
+			}
+		}
+	case "command":
+		if f.Command == nil || len(f.Command.Run) == 0 {
+			return errors.New("command feeds require a command to run")
+		}
+		if f.Command.Timeout != "" {
+			var err error
+			if f.Command.TimeoutDuration, err = time.ParseDuration(f.Command.Timeout); err != nil {
+				return fmt.Errorf("invalid command timeout: %w", err)
+			}
+		}
+		switch f.Command.Format {
+		case "", "json", "text":
+		default:
+			return fmt.Errorf("command format '%v' not found", f.Command.Format)
+		}
+	default:
+		return fmt.Errorf("feed type '%v' not found", f.Type)
+	}
+
+	if f.Query.OnError != nil {
+		for _, es := range f.Query.OnError.Store {
+			for _, fs := range f.Store {
+				if fs.Name == es.Name {
+					return fmt.Errorf("error store '%v' is already a store name", es.Name)
+				}
+			}
+		}
+	}
+
+	if f.Assert != nil {
+		for path, t := range f.Assert.Types {
+			switch t {
+			case "string", "number", "bool", "array", "object", "null":
+			default:
+				return fmt.Errorf("assert type '%v' for '%v' not found", t, path)
+			}
+		}
+
+		if len(f.Assert.Schema) > 0 {
+			if _, err := f.Assert.CompileSchema(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if f.Query.GraphQL != nil && f.Query.Pagination != nil {
+		return errors.New("pagination is not supported with graphql")
+	}
+
+	if p := f.Query.Pagination; p != nil {
+		switch p.Type {
+		case "link":
+		case "cursor":
+			if p.Cursor == "" || p.Param == "" {
+				return errors.New("cursor pagination requires cursor and param")
+			}
+		case "page":
+			if p.Param == "" {
+				return errors.New("page pagination requires param")
+			}
+		case "offset":
+			if p.Param == "" || p.Size <= 0 {
+				return errors.New("offset pagination requires param and size")
+			}
+		default:
+			return fmt.Errorf("pagination type '%v' not found", p.Type)
+		}
+	}
+
+	return nil
+}
+
+// splitValue splits a value reference of "feed.store" into its parts
+func splitValue(value string) (string, string) {
+	split := strings.SplitN(value, ".", 2)
+	if len(split) < 2 {
+		return split[0], ""
+	}
+	return split[0], split[1]
 }
 
 type FeedConfig struct {
-	Name     string       `yaml:"name"`
-	Query    FeedQuery    `yaml:"query"`
-	Schedule FeedSchedule `yaml:"schedule"`
-	Store    []FeedStore  `yaml:"store"`
+	Name       string          `yaml:"name"`
+	Type       string          `yaml:"type,omitempty"`
+	Query      FeedQuery       `yaml:"query"`
+	Prometheus *FeedPrometheus `yaml:"prometheus,omitempty"`
+	Command    *FeedCommand    `yaml:"command,omitempty"`
+	Push       *FeedPush       `yaml:"push,omitempty"`
+	SQL        *FeedSQL        `yaml:"sql,omitempty"`
+	Redis      *FeedRedis      `yaml:"redis,omitempty"`
+	Assert     *FeedAssert     `yaml:"assert,omitempty"`
+	Schedule   FeedSchedule    `yaml:"schedule"`
+	Store      []FeedStore     `yaml:"store"`
+	ForEach    *FeedForEach    `yaml:"forEach,omitempty"`
+
+	// Group and Vars are set on feeds expanded from a forEach
+	Group string            `yaml:"-"`
+	Vars  map[string]string `yaml:"-"`
+}
+
+// FeedForEach expands a single feed into one feed per item,
+// each item is a set of variables available to query templates as `{{.var}}`.
+// Expanded feeds are named "<feed>_<item[key]>" with key defaulting to "name".
+type FeedForEach struct {
+	Key   string              `yaml:"key,omitempty"`
+	Items []map[string]string `yaml:"items"`
 }
 
 type FeedQuery struct {
-	Headers map[string]string `yaml:"headers"`
-	Params  map[string]string `yaml:"params"`
-	Url     string            `yaml:"url"`
-	Method  string            `yaml:"method"`
-	Body    string            `yaml:"body"`
-	Status  int               `yaml:"status"`
+	Headers    map[string]string `yaml:"headers"`
+	Params     map[string]string `yaml:"params"`
+	Url        string            `yaml:"url"`
+	Method     string            `yaml:"method"`
+	Body       string            `yaml:"body"`
+	Status     StatusCodes       `yaml:"status"`
+	Pagination *FeedPagination   `yaml:"pagination,omitempty"`
+	GraphQL    *FeedGraphQL      `yaml:"graphql,omitempty"`
+	OnError    *FeedOnError      `yaml:"onError,omitempty"`
+}
+
+// FeedOnError handles responses that do not match the status codes,
+// store paths are applied to the error body and are cleared on success.
+// Mark stale flags the feed values as stale until the next success.
+type FeedOnError struct {
+	Store     []FeedStore `yaml:"store,omitempty"`
+	MarkStale bool        `yaml:"markStale,omitempty"`
+}
+
+// FeedGraphQL sends a graphql query as the request body,
+// string variables may reference secrets and templates like headers.
+// Store paths are applied to the `data` of the response.
+type FeedGraphQL struct {
+	Query     string                 `yaml:"query"`
+	Variables map[string]interface{} `yaml:"variables,omitempty"`
+}
+
+// FeedPagination requests multiple pages and merges them into a single
+// array of items before store paths are applied.
+// Supported types are:
+//
+//	link:   follow the `Link` header with rel="next"
+//	cursor: read the next cursor from the json path `cursor` into the `param` query param
+//	page:   increment the `param` query param starting from `start`, default 1
+//	offset: increment the `param` query param by `size` starting from `start`
+type FeedPagination struct {
+	Type      string `yaml:"type"`
+	Items     string `yaml:"items,omitempty"`
+	Cursor    string `yaml:"cursor,omitempty"`
+	Param     string `yaml:"param,omitempty"`
+	Start     int    `yaml:"start,omitempty"`
+	Size      int    `yaml:"size,omitempty"`
+	SizeParam string `yaml:"sizeParam,omitempty"`
+	MaxPages  int    `yaml:"maxPages,omitempty"`
+}
+
+// FeedPrometheus runs an instant query, or a range query when range is set.
+// Results are converted to json with `value` as the first sample,
+// `values` as all values of the first series and `series` for every series
+// with its `labels`, range queries also include `times`.
+type FeedPrometheus struct {
+	Url     string               `yaml:"url"`
+	Query   string               `yaml:"query"`
+	Headers map[string]string    `yaml:"headers,omitempty"`
+	Range   *FeedPrometheusRange `yaml:"range,omitempty"`
+}
+
+type FeedPrometheusRange struct {
+	Since string `yaml:"since"`
+	Step  string `yaml:"step"`
+
+	// SinceDuration and StepDuration are parsed from since and step
+	SinceDuration time.Duration `yaml:"-"`
+	StepDuration  time.Duration `yaml:"-"`
+}
+
+// FeedCommand runs a local command and reads stdout as json,
+// or with a text format as `{"text": "...", "lines": ["..."]}`.
+type FeedCommand struct {
+	Run     []string          `yaml:"run"`
+	Dir     string            `yaml:"dir,omitempty"`
+	Env     map[string]string `yaml:"env,omitempty"`
+	Timeout string            `yaml:"timeout,omitempty"`
+	Format  string            `yaml:"format,omitempty"`
+
+	// TimeoutDuration is the parsed timeout, zero when the default is used
+	TimeoutDuration time.Duration `yaml:"-"`
+}
+
+// FeedPush receives values posted to `/api/push/{feed}` instead of polling.
+// The body must be signed using the secret with a hex HMAC SHA256 in the
+// header, default `X-Signature-256`, optionally prefixed with "sha256=".
+// Unsigned pushes are only accepted when insecure is set without a secret.
+type FeedPush struct {
+	Secret   string `yaml:"secret,omitempty"`
+	Header   string `yaml:"header,omitempty"`
+	Insecure bool   `yaml:"insecure,omitempty"`
+}
+
+// FeedSQL runs a query against a postgres, mysql or sqlite3 database.
+// Results are converted to json with `value` as the first column of the
+// first row, `row` as the first row, `rows` as every row and `columns`
+// as an array of values per column.
+// Args are templated and bound to the query placeholders, `$1` for postgres
+// and `?` for mysql and sqlite3. Values from feeds, vars or secrets belong in
+// args and not the query text, which is run as is after templating.
+type FeedSQL struct {
+	Driver       string   `yaml:"driver"`
+	DSN          string   `yaml:"dsn"`
+	Query        string   `yaml:"query"`
+	Args         []string `yaml:"args,omitempty"`
+	Timeout      string   `yaml:"timeout,omitempty"`
+	MaxOpenConns int      `yaml:"maxOpenConns,omitempty"`
+
+	// TimeoutDuration is timeout parsed when loading, zero if not set
+	TimeoutDuration time.Duration `yaml:"-"`
+}
+
+// FeedRedis reads keys from redis, using the same connection as the store
+// unless a url is provided. Each key is written to the json by name.
+// Supported key types are:
+//
+//	string: GET, numbers are converted, the default
+//	hash:   HGETALL as an object
+//	list:   LRANGE from start to stop
+//	zrange: ZRANGE from start to stop with scores as `[{"member", "score"}]`
+//	llen, scard, zcard, xlen: length of a list, set, sorted set or stream
+type FeedRedis struct {
+	Url  string         `yaml:"url,omitempty"`
+	Keys []FeedRedisKey `yaml:"keys"`
+}
+
+type FeedRedisKey struct {
+	Name    string `yaml:"name"`
+	Key     string `yaml:"key"`
+	Type    string `yaml:"type,omitempty"`
+	Start   int64  `yaml:"start,omitempty"`
+	Stop    *int64 `yaml:"stop,omitempty"`
+	Reverse bool   `yaml:"reverse,omitempty"`
+}
+
+// FeedAssert validates the fetched json before any values are stored,
+// failures keep the previous values and mark the feed unhealthy.
+// Types are one of string, number, bool, array, object or null,
+// the schema is a JSON Schema written in yaml.
+type FeedAssert struct {
+	Required []string               `yaml:"required,omitempty"`
+	Types    map[string]string      `yaml:"types,omitempty"`
+	Schema   map[string]interface{} `yaml:"schema,omitempty"`
 }
 
 type FeedSchedule struct {
 	Every string `yaml:"every"`
 }
 
+// FeedStore saves the value at path, arrays and objects are kept as json
+// so IsArray is only kept for older configs.
 type FeedStore struct {
-	Name    string `yaml:"name"`
-	Path    string `yaml:"path"`
-	IsArray bool   `yaml:"isArray,omitempty"`
+	Name      string      `yaml:"name"`
+	Path      string      `yaml:"path"`
+	IsArray   bool        `yaml:"isArray,omitempty"`
+	Transform []Transform `yaml:"transform,omitempty"`
 }
 
 func (c *Config) FeedByName(name string) *FeedConfig {
@@ -62,6 +474,198 @@ func (c *Config) FeedByName(name string) *FeedConfig {
 	return nil
 }
 
+// expandFeeds replaces any forEach feeds with one feed per item
+func (c *Config) expandFeeds() error {
+	expanded := make([]FeedConfig, 0, len(c.Feeds))
+	for _, f := range c.Feeds {
+		if f.ForEach == nil {
+			expanded = append(expanded, f)
+			continue
+		}
+
+		key := f.ForEach.Key
+		if key == "" {
+			key = "name"
+		}
+
+		for i, item := range f.ForEach.Items {
+			if item[key] == "" {
+				return fmt.Errorf("feed '%v' item %v is missing key '%v'", f.Name, i, key)
+			}
+
+			instance := f
+			instance.Name = f.Name + "_" + item[key]
+			if !dashboardNameRegex.MatchString(instance.Name) {
+				return fmt.Errorf("feed name '%v' may only use letters, numbers, _ and -", instance.Name)
+			}
+			instance.ForEach = nil
+			instance.Group = f.Name
+			instance.Vars = item
+			expanded = append(expanded, instance)
+		}
+	}
+
+	names := make(map[string]bool, len(expanded))
+	for _, f := range expanded {
+		if names[f.Name] {
+			return fmt.Errorf("feed name '%v' is used more than once", f.Name)
+		}
+		names[f.Name] = true
+	}
+
+	c.Feeds = expanded
+	return nil
+}
+
+// FeedsByGroup returns all feeds expanded from the named forEach feed
+func (c *Config) FeedsByGroup(group string) []FeedConfig {
+	var feeds []FeedConfig
+	for _, f := range c.Feeds {
+		if f.Group == group {
+			feeds = append(feeds, f)
+		}
+	}
+	return feeds
+}
+
+var feedRefRegex = regexp.MustCompile(`{{[^}]*\bfeeds\.(\w+)`)
+
+// Dependencies returns the names of other feeds referenced by
+// the query, such as `{{feeds.repos.latestId}}`
+func (f *FeedConfig) Dependencies() []string {
+	texts := []string{f.Query.Url, f.Query.Body}
+	for _, v := range f.Query.Params {
+		texts = append(texts, v)
+	}
+	for _, v := range f.Query.Headers {
+		texts = append(texts, v)
+	}
+	if f.Prometheus != nil {
+		texts = append(texts, f.Prometheus.Url, f.Prometheus.Query)
+		for _, v := range f.Prometheus.Headers {
+			texts = append(texts, v)
+		}
+	}
+	if f.Command != nil {
+		texts = append(texts, f.Command.Run...)
+		for _, v := range f.Command.Env {
+			texts = append(texts, v)
+		}
+	}
+	if f.SQL != nil {
+		texts = append(texts, f.SQL.Query)
+		texts = append(texts, f.SQL.Args...)
+	}
+	if f.Redis != nil {
+		texts = append(texts, f.Redis.Url)
+		for _, k := range f.Redis.Keys {
+			texts = append(texts, k.Key)
+		}
+	}
+	if gql := f.Query.GraphQL; gql != nil {
+		texts = append(texts, gql.Query)
+		for _, v := range gql.Variables {
+			if str, ok := v.(string); ok {
+				texts = append(texts, str)
+			}
+		}
+	}
+
+	var deps []string
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, match := range feedRefRegex.FindAllStringSubmatch(text, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				deps = append(deps, match[1])
+			}
+		}
+	}
+	return deps
+}
+
+// sortFeeds orders feeds so each feed comes after its dependencies,
+// returning an error for unknown feeds or dependency cycles.
+func (c *Config) sortFeeds() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	states := make(map[string]int, len(c.Feeds))
+	sorted := make([]FeedConfig, 0, len(c.Feeds))
+
+	var visit func(feed *FeedConfig, path []string) error
+	visit = func(feed *FeedConfig, path []string) error {
+		path = append(path, feed.Name)
+		switch states[feed.Name] {
+		case visiting:
+			return fmt.Errorf("feed dependency cycle: %v", strings.Join(path, " -> "))
+		case visited:
+			return nil
+		}
+
+		states[feed.Name] = visiting
+		for _, dep := range feed.Dependencies() {
+			depFeed := c.FeedByName(dep)
+			if depFeed == nil {
+				return fmt.Errorf("feed '%v' references unknown feed '%v'", feed.Name, dep)
+			}
+
+			if err := visit(depFeed, path); err != nil {
+				return err
+			}
+		}
+		states[feed.Name] = visited
+
+		sorted = append(sorted, *feed)
+		return nil
+	}
+
+	for i := range c.Feeds {
+		if err := visit(&c.Feeds[i], nil); err != nil {
+			return err
+		}
+	}
+
+	c.Feeds = sorted
+	return nil
+}
+
+func (f *FeedConfig) IsPush() bool {
+	return strings.ToLower(f.Type) == "push"
+}
+
+// AllStores returns the feed stores and any error stores
+func (f *FeedConfig) AllStores() []FeedStore {
+	if f.Query.OnError == nil {
+		return f.Store
+	}
+
+	stores := make([]FeedStore, 0, len(f.Store)+len(f.Query.OnError.Store))
+	stores = append(stores, f.Store...)
+	return append(stores, f.Query.OnError.Store...)
+}
+
+func (f *FeedConfig) StoreByName(name string) *FeedStore {
+	for _, s := range f.AllStores() {
+		if s.Name == name {
+			return &s
+		}
+	}
+	return nil
+}
+
+func (c *Config) NotifierByName(name string) *NotifierConfig {
+	for _, n := range c.Notifiers {
+		if n.Name == name {
+			return &n
+		}
+	}
+	return nil
+}
+
 // EnvConfig contains values expected from the environment
 type EnvConfig struct {
 	RedisUrl      string `env:"REDIS_URL"`
@@ -77,8 +681,11 @@ type EnvConfig struct {
 
 /*
 	Dashboard is the visual UI dashboard and its config
-	It starts out at the base HTML level with meta and custom layouts / styles ( later )
-	It also defines how many columns the grid should have
+	The main dashboard is served at / and named dashboards at /d/{name}
+	It starts out at the base HTML level with meta and custom layouts / styles
+	Custom styles and style files are added after the default styles,
+	style files starting with static: are read from the static files
+	It also defines the grid layers are placed on, see Grid
 
 	Then you can define a number of layers:
 		Each layer can have basic content or contents
@@ -88,22 +695,70 @@ type Dashboard struct {
 	Title        string            `yaml:"title"`
 	Meta         map[string]string `yaml:"meta,omitempty"`
 	CustomStyles map[string]string `yaml:"customStyles,omitempty"`
+	StyleFiles   []string          `yaml:"styleFiles,omitempty"`
+	Theme        ThemeConfig       `yaml:"theme,omitempty"`
+	Grid         Grid              `yaml:"grid,omitempty"`
 	Layers       []Layer           `yaml:"layers"`
 }
 
 type Layer struct {
 	Name     string    `yaml:"name"`
 	Title    string    `yaml:"title,omitempty"`
+	ForEach  string    `yaml:"forEach,omitempty"`
 	X        int       `yaml:"x"`
 	Y        int       `yaml:"y"`
 	Width    int       `yaml:"width"`
 	Height   int       `yaml:"height"`
 	Layout   string    `yaml:"layout"`
 	Contents []Content `yaml:"contents"`
+	// Icon is shown before the title, either text like an emoji or an image url
+	Icon string `yaml:"icon,omitempty"`
+	// Link makes the title a link
+	Link string `yaml:"link,omitempty"`
+	// Updated shows when a feed or `feed.store` value was last updated in the title bar
+	Updated string `yaml:"updated,omitempty"`
+	// Css is an inline style for this layer
+	Css string `yaml:"css,omitempty"`
+	// Layers are child layers placed on their own grid inside this layer
+	Grid   Grid    `yaml:"grid,omitempty"`
+	Layers []Layer `yaml:"layers,omitempty"`
 }
 
 type Content struct {
-	Type   string   `yaml:"type"`
-	Styles []string `yaml:"styles"`
-	Text   string   `yaml:"text"`
+	Type   string        `yaml:"type"`
+	Styles []string      `yaml:"styles"`
+	Text   string        `yaml:"text"`
+	Value  string        `yaml:"value,omitempty"`
+	Rules  []ContentRule `yaml:"rules,omitempty"`
+	// ShowAge displays how long ago the value was last updated
+	ShowAge bool `yaml:"showAge,omitempty"`
+	// StaleAfter dims the content when the value is older than this duration
+	StaleAfter string `yaml:"staleAfter,omitempty"`
+	// Css is an inline style for this content
+	Css string `yaml:"css,omitempty"`
+
+	// StaleAfterDuration is the parsed staleAfter for the page scripts
+	StaleAfterDuration time.Duration `yaml:"-"`
+}
+
+func (c *Content) validate() error {
+	if (c.ShowAge || c.StaleAfter != "") && c.Value == "" {
+		return errors.New("showAge and staleAfter require a value")
+	}
+
+	if c.StaleAfter != "" {
+		var err error
+		if c.StaleAfterDuration, err = time.ParseDuration(c.StaleAfter); err != nil {
+			return fmt.Errorf("invalid staleAfter: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ContentRule adds a style to content when the content value
+// matches the condition, only the first matching rule is applied.
+type ContentRule struct {
+	When  Condition `yaml:"when"`
+	Style string    `yaml:"style"`
 }
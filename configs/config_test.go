@@ -0,0 +1,293 @@
+package configs
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestExpandFeeds(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		feeds    string
+		expected []string
+		fails    bool
+	}{
+		{
+			name: "items",
+			feeds: `
+- name: repo
+  forEach: {items: [{name: dashy}, {name: go-redis}]}
+- name: weather`,
+			expected: []string{"repo_dashy", "repo_go-redis", "weather"},
+		},
+		{
+			name: "custom key",
+			feeds: `
+- name: repo
+  forEach: {key: id, items: [{id: "1"}]}`,
+			expected: []string{"repo_1"},
+		},
+		{
+			name:  "missing key",
+			feeds: "[{name: repo, forEach: {items: [{id: a}]}}]",
+			fails: true,
+		},
+		{
+			name:  "invalid name",
+			feeds: "[{name: repo, forEach: {items: [{name: a/b}]}}]",
+			fails: true,
+		},
+		{
+			name:  "duplicate item",
+			feeds: "[{name: repo, forEach: {items: [{name: a}, {name: a}]}}]",
+			fails: true,
+		},
+		{
+			name:  "duplicate feed",
+			feeds: "[{name: repo_a}, {name: repo, forEach: {items: [{name: a}]}}]",
+			fails: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var c Config
+			if err := yaml.Unmarshal([]byte(tc.feeds), &c.Feeds); err != nil {
+				t.Fatalf("unable to parse feeds: %v", err)
+			}
+
+			err := c.expandFeeds()
+			if tc.fails {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(c.Feeds) != len(tc.expected) {
+				t.Fatalf("expected %v feeds, got %v", len(tc.expected), len(c.Feeds))
+			}
+			for i, name := range tc.expected {
+				if c.Feeds[i].Name != name {
+					t.Errorf("expected feed %v to be %v, got %v", i, name, c.Feeds[i].Name)
+				}
+			}
+		})
+	}
+}
+
+func TestFeedDependencies(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		feed     string
+		expected []string
+	}{
+		{name: "none", feed: "{name: a, query: {url: 'https://example.com'}}"},
+		{
+			name:     "url and params",
+			feed:     "{name: a, query: {url: '{{feeds.b.url}}', params: {id: '{{ feeds.c.id }}'}}}",
+			expected: []string{"b", "c"},
+		},
+		{
+			name:     "repeated",
+			feed:     "{name: a, query: {url: '{{feeds.b.x}}/{{feeds.b.y}}'}}",
+			expected: []string{"b"},
+		},
+		{
+			name:     "sql args",
+			feed:     "{name: a, type: sql, sql: {query: 'select ?', args: ['{{feeds.b.id}}']}}",
+			expected: []string{"b"},
+		},
+		{
+			name:     "command env",
+			feed:     "{name: a, type: command, command: {run: [ls], env: {ID: '{{feeds.b.id}}'}}}",
+			expected: []string{"b"},
+		},
+		{
+			name:     "prometheus headers",
+			feed:     "{name: a, type: prometheus, prometheus: {url: x, query: up, headers: {X-Org: '{{feeds.b.org}}'}}}",
+			expected: []string{"b"},
+		},
+		{
+			name:     "redis url",
+			feed:     "{name: a, type: redis, redis: {url: '{{feeds.b.url}}', keys: [{name: k, key: k}]}}",
+			expected: []string{"b"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var feed FeedConfig
+			if err := yaml.Unmarshal([]byte(tc.feed), &feed); err != nil {
+				t.Fatalf("unable to parse feed: %v", err)
+			}
+
+			deps := feed.Dependencies()
+			sort.Strings(deps)
+			if strings.Join(deps, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("expected %v, got %v", tc.expected, deps)
+			}
+		})
+	}
+}
+
+func TestSortFeeds(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		feeds    string
+		expected []string
+		fails    bool
+	}{
+		{
+			name:     "independent",
+			feeds:    "[{name: a}, {name: b}]",
+			expected: []string{"a", "b"},
+		},
+		{
+			name: "dependency first",
+			feeds: `
+- {name: details, query: {url: "/repos/{{feeds.repos.latestId}}"}}
+- {name: repos, query: {url: "/repos"}}`,
+			expected: []string{"repos", "details"},
+		},
+		{
+			name: "chain",
+			feeds: `
+- {name: c, query: {url: "{{feeds.b.x}}"}}
+- {name: b, query: {url: "{{feeds.a.x}}"}}
+- {name: a}`,
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:  "unknown feed",
+			feeds: `[{name: a, query: {url: "{{feeds.missing.x}}"}}]`,
+			fails: true,
+		},
+		{
+			name:  "self reference",
+			feeds: `[{name: a, query: {url: "{{feeds.a.x}}"}}]`,
+			fails: true,
+		},
+		{
+			name: "cycle",
+			feeds: `
+- {name: a, query: {url: "{{feeds.b.x}}"}}
+- {name: b, query: {body: "{{feeds.a.x}}"}}`,
+			fails: true,
+		},
+		{
+			name: "sql args cycle",
+			feeds: `
+- {name: a, type: sql, sql: {query: "select ?", args: ["{{feeds.b.x}}"]}}
+- {name: b, type: sql, sql: {query: "select ?", args: ["{{feeds.a.x}}"]}}`,
+			fails: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var c Config
+			if err := yaml.Unmarshal([]byte(tc.feeds), &c.Feeds); err != nil {
+				t.Fatalf("unable to parse feeds: %v", err)
+			}
+
+			err := c.sortFeeds()
+			if tc.fails {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var names []string
+			for _, f := range c.Feeds {
+				names = append(names, f.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("expected %v, got %v", tc.expected, names)
+			}
+		})
+	}
+}
+
+func TestNewConfigDurations(t *testing.T) {
+	c, err := NewConfig([]byte(`
+rateLimits:
+  - {host: api.example.com, requests: 10, per: 1m}
+feeds:
+  - name: jobs
+    type: sql
+    sql: {driver: sqlite3, dsn: jobs.db, query: "select 1", timeout: 5s}
+    store: [{name: count, path: count}]
+  - name: disk
+    type: command
+    command: {run: [df], timeout: 2s}
+  - name: cpu
+    type: prometheus
+    prometheus: {url: "http://prometheus", query: up, range: {since: 1h, step: 30s}}
+alerts:
+  - {name: stuck, value: jobs.count, when: value > 10, resendEvery: 1h}
+kiosk:
+  every: 45s
+dashboard:
+  layers:
+    - name: parent
+      width: 1
+      height: 1
+      layers:
+        - name: child
+          width: 1
+          height: 1
+          contents: [{type: text, value: jobs.count, staleAfter: 10m}]
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		got      time.Duration
+		expected time.Duration
+	}{
+		{name: "rate limit per", got: c.RateLimits[0].PerDuration, expected: time.Minute},
+		{name: "sql timeout", got: c.FeedByName("jobs").SQL.TimeoutDuration, expected: 5 * time.Second},
+		{name: "command timeout", got: c.FeedByName("disk").Command.TimeoutDuration, expected: 2 * time.Second},
+		{name: "prometheus since", got: c.FeedByName("cpu").Prometheus.Range.SinceDuration, expected: time.Hour},
+		{name: "prometheus step", got: c.FeedByName("cpu").Prometheus.Range.StepDuration, expected: 30 * time.Second},
+		{name: "alert resend", got: c.Alerts[0].ResendEveryDuration, expected: time.Hour},
+		{name: "kiosk every", got: c.Kiosk.EveryDuration, expected: 45 * time.Second},
+		{
+			name:     "content stale after",
+			got:      c.Dashboard.Layers[0].Layers[0].Contents[0].StaleAfterDuration,
+			expected: 10 * time.Minute,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, tc.got)
+			}
+		})
+	}
+}
+
+func TestNewConfigInvalidDurations(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config string
+	}{
+		{name: "rate limit per", config: "rateLimits: [{host: a, requests: 1, per: soon}]"},
+		{name: "zero rate limit per", config: "rateLimits: [{host: a, requests: 1, per: 0s}]"},
+		{name: "command timeout", config: "feeds: [{name: a, type: command, command: {run: [ls], timeout: x}}]"},
+		{name: "kiosk every", config: "kiosk: {every: often}"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewConfig([]byte(tc.config)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
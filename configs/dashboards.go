@@ -0,0 +1,109 @@
+package configs
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+var dashboardNameRegex = regexp.MustCompile(`^[\w-]+$`)
+
+// KioskConfig rotates through dashboard pages, it is enabled by
+// opening any page with `?kiosk` such as on a wall mounted screen.
+type KioskConfig struct {
+	Every string `yaml:"every"`
+	// Pages are the paths to rotate through, `/` or `/d/{name}`, default all pages
+	Pages []string `yaml:"pages,omitempty"`
+	// EveryDuration is every parsed when the config is validated
+	EveryDuration time.Duration `yaml:"-"`
+}
+
+// DashboardPath returns the path a named dashboard is served at
+func DashboardPath(name string) string {
+	return "/d/" + name
+}
+
+// HasRootDashboard is true when the main dashboard should be served,
+// it can be left out when only named dashboards are used.
+func (c *Config) HasRootDashboard() bool {
+	return len(c.Dashboards) == 0 || len(c.Dashboard.Layers) > 0
+}
+
+// DashboardNames returns the names of all named dashboards in order
+func (c *Config) DashboardNames() []string {
+	names := make([]string, 0, len(c.Dashboards))
+	for name := range c.Dashboards {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PagePaths returns the path of every dashboard, the main dashboard first
+func (c *Config) PagePaths() []string {
+	var paths []string
+	if c.HasRootDashboard() {
+		paths = append(paths, "/")
+	}
+	for _, name := range c.DashboardNames() {
+		paths = append(paths, DashboardPath(name))
+	}
+	return paths
+}
+
+func (c *Config) validateDashboards() error {
+	if err := c.validateThemes(); err != nil {
+		return err
+	}
+
+	if err := c.validateTheme(c.Dashboard.Theme); err != nil {
+		return err
+	}
+
+	if err := c.validateLayers(c.Dashboard.Grid, c.Dashboard.Layers); err != nil {
+		return err
+	}
+
+	for _, name := range c.DashboardNames() {
+		if !dashboardNameRegex.MatchString(name) {
+			return fmt.Errorf("dashboard name '%v' may only use letters, numbers, _ and -", name)
+		}
+
+		d := c.Dashboards[name]
+		if err := c.validateTheme(d.Theme); err != nil {
+			return fmt.Errorf("invalid dashboard '%v': %w", name, err)
+		}
+
+		if err := c.validateLayers(d.Grid, d.Layers); err != nil {
+			return fmt.Errorf("invalid dashboard '%v': %w", name, err)
+		}
+	}
+
+	if c.Kiosk == nil {
+		return nil
+	}
+
+	var err error
+	if c.Kiosk.EveryDuration, err = time.ParseDuration(c.Kiosk.Every); err != nil {
+		return fmt.Errorf("kiosk has invalid every: %w", err)
+	}
+
+	paths := c.PagePaths()
+	for _, page := range c.Kiosk.Pages {
+		if !containsString(paths, page) {
+			return fmt.Errorf("kiosk references unknown page '%v'", page)
+		}
+	}
+
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
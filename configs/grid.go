@@ -0,0 +1,77 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	defaultGridColumns = 3
+	defaultGridGap     = ".25rem"
+)
+
+// Grid is the layout of the dashboard body, layers are placed
+// on the grid using their x, y, width and height.
+type Grid struct {
+	Columns int `yaml:"columns,omitempty"`
+	// RowHeight is the css height of each row, rows fit their content by default
+	RowHeight string `yaml:"rowHeight,omitempty"`
+	Gap       string `yaml:"gap,omitempty"`
+	// Breakpoints change the number of columns on smaller screens,
+	// layers then flow into the grid in order instead of using x and y.
+	Breakpoints []Breakpoint `yaml:"breakpoints,omitempty"`
+}
+
+type Breakpoint struct {
+	MaxWidth string `yaml:"maxWidth"`
+	Columns  int    `yaml:"columns"`
+}
+
+// ColumnCount returns the configured columns or the default of 3
+func (g Grid) ColumnCount() int {
+	if g.Columns <= 0 {
+		return defaultGridColumns
+	}
+	return g.Columns
+}
+
+// GapSize returns the configured gap or the default of .25rem
+func (g Grid) GapSize() string {
+	if g.Gap == "" {
+		return defaultGridGap
+	}
+	return g.Gap
+}
+
+func (g Grid) validate() error {
+	if g.Columns < 0 {
+		return errors.New("grid columns can not be negative")
+	}
+
+	for _, b := range g.Breakpoints {
+		if b.MaxWidth == "" || b.Columns <= 0 {
+			return errors.New("grid breakpoints require a maxWidth and columns")
+		}
+	}
+
+	return nil
+}
+
+// validateLayer checks the layer fits within the grid columns,
+// repeated forEach layers only use their width and height.
+func (g Grid) validateLayer(l Layer) error {
+	if l.Width < 1 || l.Height < 1 {
+		return errors.New("width and height must be at least 1")
+	}
+
+	if l.ForEach == "" && (l.X < 0 || l.Y < 0) {
+		return errors.New("x and y can not be negative")
+	}
+
+	columns := g.ColumnCount()
+	if l.X+l.Width > columns {
+		return fmt.Errorf("does not fit in the %v grid columns", columns)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package configs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StatusCodes matches response status codes, in yaml it can be a single
+// code, a class like `2xx`, a range like `200-204` or a list of any of those.
+// No status codes matches any 2xx status.
+type StatusCodes []StatusRange
+
+type StatusRange struct {
+	Min int
+	Max int
+}
+
+func ParseStatusRange(value string) (StatusRange, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+
+	if len(value) == 3 && strings.HasSuffix(value, "xx") {
+		class, err := strconv.Atoi(value[:1])
+		if err != nil {
+			return StatusRange{}, fmt.Errorf("invalid status class '%v'", value)
+		}
+		return StatusRange{Min: class * 100, Max: class*100 + 99}, nil
+	}
+
+	if split := strings.SplitN(value, "-", 2); len(split) == 2 {
+		min, err := strconv.Atoi(strings.TrimSpace(split[0]))
+		if err != nil {
+			return StatusRange{}, fmt.Errorf("invalid status range '%v'", value)
+		}
+		max, err := strconv.Atoi(strings.TrimSpace(split[1]))
+		if err != nil || max < min {
+			return StatusRange{}, fmt.Errorf("invalid status range '%v'", value)
+		}
+		return StatusRange{Min: min, Max: max}, nil
+	}
+
+	code, err := strconv.Atoi(value)
+	if err != nil {
+		return StatusRange{}, fmt.Errorf("invalid status code '%v'", value)
+	}
+	return StatusRange{Min: code, Max: code}, nil
+}
+
+func (s *StatusCodes) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var values []string
+	if err := unmarshal(&values); err != nil {
+		var value string
+		if err := unmarshal(&value); err != nil {
+			return err
+		}
+		values = []string{value}
+	}
+
+	codes := make(StatusCodes, 0, len(values))
+	for _, v := range values {
+		r, err := ParseStatusRange(v)
+		if err != nil {
+			return err
+		}
+		codes = append(codes, r)
+	}
+
+	*s = codes
+	return nil
+}
+
+func (s StatusCodes) Matches(code int) bool {
+	if len(s) == 0 {
+		return code >= 200 && code <= 299
+	}
+
+	for _, r := range s {
+		if code >= r.Min && code <= r.Max {
+			return true
+		}
+	}
+	return false
+}
+
+func (s StatusCodes) String() string {
+	if len(s) == 0 {
+		return "2xx"
+	}
+
+	ranges := make([]string, len(s))
+	for i, r := range s {
+		if r.Min == r.Max {
+			ranges[i] = strconv.Itoa(r.Min)
+		} else {
+			ranges[i] = fmt.Sprintf("%v-%v", r.Min, r.Max)
+		}
+	}
+	return strings.Join(ranges, ", ")
+}
@@ -0,0 +1,77 @@
+package configs
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestParseStatusRange(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		expected StatusRange
+		fails    bool
+	}{
+		{value: "200", expected: StatusRange{Min: 200, Max: 200}},
+		{value: " 404 ", expected: StatusRange{Min: 404, Max: 404}},
+		{value: "2xx", expected: StatusRange{Min: 200, Max: 299}},
+		{value: "4XX", expected: StatusRange{Min: 400, Max: 499}},
+		{value: "200-204", expected: StatusRange{Min: 200, Max: 204}},
+		{value: "200 - 204", expected: StatusRange{Min: 200, Max: 204}},
+		{value: "204-200", fails: true},
+		{value: "axx", fails: true},
+		{value: "2x", fails: true},
+		{value: "ok", fails: true},
+		{value: "200-", fails: true},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			r, err := ParseStatusRange(tc.value)
+			if tc.fails {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", r)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if r != tc.expected {
+				t.Errorf("expected %+v, got %+v", tc.expected, r)
+			}
+		})
+	}
+}
+
+func TestStatusCodes(t *testing.T) {
+	for _, tc := range []struct {
+		yaml    string
+		str     string
+		matches []int
+		misses  []int
+	}{
+		{yaml: "method: GET", str: "2xx", matches: []int{200, 299}, misses: []int{199, 304}},
+		{yaml: "status: 200", str: "200", matches: []int{200}, misses: []int{201}},
+		{yaml: "status: 2xx", str: "200-299", matches: []int{204}, misses: []int{404}},
+		{yaml: "status: [200, 404, 500-503]", str: "200, 404, 500-503", matches: []int{200, 404, 502}, misses: []int{201, 504}},
+	} {
+		t.Run(tc.yaml, func(t *testing.T) {
+			var query FeedQuery
+			if err := yaml.Unmarshal([]byte(tc.yaml), &query); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if query.Status.String() != tc.str {
+				t.Errorf("expected '%v', got '%v'", tc.str, query.Status)
+			}
+			for _, code := range tc.matches {
+				if !query.Status.Matches(code) {
+					t.Errorf("expected %v to match", code)
+				}
+			}
+			for _, code := range tc.misses {
+				if query.Status.Matches(code) {
+					t.Errorf("expected %v to not match", code)
+				}
+			}
+		})
+	}
+}
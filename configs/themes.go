@@ -0,0 +1,257 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+)
+
+const defaultTheme = "nord"
+
+// builtinThemes are css variables by theme name, every theme sets
+// the same variables so they can be used by default and custom styles.
+var builtinThemes = map[string]map[string]string{
+	"nord": {
+		"layer0":   "#2e3440",
+		"layer1":   "#3b4252",
+		"layer2":   "#434c5e",
+		"layer3":   "#4c566a",
+		"primary2": "#d8dee9",
+		"primary1": "#e5e9f0",
+		"primary":  "#eceff4",
+		"accent1":  "#8fbcbb",
+		"accent":   "#88c0d0",
+		"accent2":  "#81a1c1",
+		"accent3":  "#5e81ac",
+		"error":    "#bf616a",
+		"danger":   "#d08770",
+		"warning":  "#ebcb8b",
+		"success":  "#a3be8c",
+		"neutral":  "#b48ead",
+	},
+	"nord-light": {
+		"layer0":   "#eceff4",
+		"layer1":   "#e5e9f0",
+		"layer2":   "#d8dee9",
+		"layer3":   "#c2cad8",
+		"primary2": "#4c566a",
+		"primary1": "#434c5e",
+		"primary":  "#2e3440",
+		"accent1":  "#5e9c9a",
+		"accent":   "#5e81ac",
+		"accent2":  "#4c6d96",
+		"accent3":  "#3b5a80",
+		"error":    "#bf616a",
+		"danger":   "#c0714f",
+		"warning":  "#b89238",
+		"success":  "#6f8f55",
+		"neutral":  "#8f6c8a",
+	},
+	"solarized": {
+		"layer0":   "#002b36",
+		"layer1":   "#073642",
+		"layer2":   "#0d4452",
+		"layer3":   "#586e75",
+		"primary2": "#839496",
+		"primary1": "#93a1a1",
+		"primary":  "#eee8d5",
+		"accent1":  "#2aa198",
+		"accent":   "#268bd2",
+		"accent2":  "#6c71c4",
+		"accent3":  "#d33682",
+		"error":    "#dc322f",
+		"danger":   "#cb4b16",
+		"warning":  "#b58900",
+		"success":  "#859900",
+		"neutral":  "#6c71c4",
+	},
+	"solarized-light": {
+		"layer0":   "#fdf6e3",
+		"layer1":   "#eee8d5",
+		"layer2":   "#e4ddc8",
+		"layer3":   "#93a1a1",
+		"primary2": "#657b83",
+		"primary1": "#586e75",
+		"primary":  "#073642",
+		"accent1":  "#2aa198",
+		"accent":   "#268bd2",
+		"accent2":  "#6c71c4",
+		"accent3":  "#d33682",
+		"error":    "#dc322f",
+		"danger":   "#cb4b16",
+		"warning":  "#b58900",
+		"success":  "#859900",
+		"neutral":  "#6c71c4",
+	},
+	"gruvbox": {
+		"layer0":   "#282828",
+		"layer1":   "#3c3836",
+		"layer2":   "#504945",
+		"layer3":   "#665c54",
+		"primary2": "#d5c4a1",
+		"primary1": "#ebdbb2",
+		"primary":  "#fbf1c7",
+		"accent1":  "#8ec07c",
+		"accent":   "#83a598",
+		"accent2":  "#458588",
+		"accent3":  "#076678",
+		"error":    "#fb4934",
+		"danger":   "#fe8019",
+		"warning":  "#fabd2f",
+		"success":  "#b8bb26",
+		"neutral":  "#d3869b",
+	},
+	"gruvbox-light": {
+		"layer0":   "#fbf1c7",
+		"layer1":   "#ebdbb2",
+		"layer2":   "#d5c4a1",
+		"layer3":   "#bdae93",
+		"primary2": "#504945",
+		"primary1": "#3c3836",
+		"primary":  "#282828",
+		"accent1":  "#427b58",
+		"accent":   "#076678",
+		"accent2":  "#458588",
+		"accent3":  "#8f3f71",
+		"error":    "#9d0006",
+		"danger":   "#af3a03",
+		"warning":  "#b57614",
+		"success":  "#79740e",
+		"neutral":  "#8f3f71",
+	},
+	"high-contrast": {
+		"layer0":   "#000000",
+		"layer1":   "#111111",
+		"layer2":   "#222222",
+		"layer3":   "#444444",
+		"primary2": "#e0e0e0",
+		"primary1": "#f0f0f0",
+		"primary":  "#ffffff",
+		"accent1":  "#00ffff",
+		"accent":   "#00e5ff",
+		"accent2":  "#40a0ff",
+		"accent3":  "#2060ff",
+		"error":    "#ff4040",
+		"danger":   "#ff8000",
+		"warning":  "#ffff00",
+		"success":  "#00ff00",
+		"neutral":  "#ff80ff",
+	},
+	"high-contrast-light": {
+		"layer0":   "#ffffff",
+		"layer1":   "#f0f0f0",
+		"layer2":   "#e0e0e0",
+		"layer3":   "#a0a0a0",
+		"primary2": "#202020",
+		"primary1": "#101010",
+		"primary":  "#000000",
+		"accent1":  "#006060",
+		"accent":   "#0040c0",
+		"accent2":  "#0030a0",
+		"accent3":  "#002080",
+		"error":    "#c00000",
+		"danger":   "#a04000",
+		"warning":  "#806000",
+		"success":  "#006000",
+		"neutral":  "#800080",
+	},
+}
+
+// ThemeConfig selects the theme of a dashboard, in yaml it can be just
+// the theme name or the themes to use when the browser prefers dark or light.
+// The dark theme is used unless a light theme is set and preferred.
+type ThemeConfig struct {
+	Dark  string `yaml:"dark,omitempty"`
+	Light string `yaml:"light,omitempty"`
+	// Toggle adds a button to switch between the dark and light theme
+	Toggle bool `yaml:"toggle,omitempty"`
+}
+
+func (t *ThemeConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var name string
+	if err := unmarshal(&name); err == nil {
+		*t = ThemeConfig{Dark: name}
+		return nil
+	}
+
+	// alias to avoid recursing into this method
+	type plain ThemeConfig
+	return unmarshal((*plain)(t))
+}
+
+// DarkTheme returns the default theme name, nord if not set
+func (t ThemeConfig) DarkTheme() string {
+	if t.Dark == "" {
+		return defaultTheme
+	}
+	return t.Dark
+}
+
+// UserTheme is a custom theme overriding the colors of a built in theme,
+// colors are css variable names without the leading `--`.
+type UserTheme struct {
+	Base   string            `yaml:"base,omitempty"`
+	Colors map[string]string `yaml:"colors"`
+}
+
+// ThemeColors returns the css variables of a built in or user theme
+func (c *Config) ThemeColors(name string) (map[string]string, error) {
+	if colors, found := builtinThemes[name]; found {
+		return colors, nil
+	}
+
+	theme, found := c.Themes[name]
+	if !found {
+		return nil, fmt.Errorf("theme '%v' not found", name)
+	}
+
+	base := theme.Base
+	if base == "" {
+		base = defaultTheme
+	}
+
+	baseColors, found := builtinThemes[base]
+	if !found {
+		return nil, fmt.Errorf("theme '%v' has unknown base '%v'", name, base)
+	}
+
+	colors := make(map[string]string, len(baseColors)+len(theme.Colors))
+	for k, v := range baseColors {
+		colors[k] = v
+	}
+	for k, v := range theme.Colors {
+		colors[k] = v
+	}
+	return colors, nil
+}
+
+func (c *Config) validateThemes() error {
+	for name := range c.Themes {
+		if _, found := builtinThemes[name]; found {
+			return fmt.Errorf("theme '%v' is already a built in theme", name)
+		}
+
+		if _, err := c.ThemeColors(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validateTheme(t ThemeConfig) error {
+	if _, err := c.ThemeColors(t.DarkTheme()); err != nil {
+		return err
+	}
+
+	if t.Light != "" {
+		if _, err := c.ThemeColors(t.Light); err != nil {
+			return err
+		}
+	}
+
+	if t.Toggle && t.Light == "" {
+		return errors.New("theme toggle requires a light theme")
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Transform is a single step applied to a stored value before it is saved,
+// in yaml it is a map with one operation and its argument such as `multiply: 1.8`.
+// Number and string operations on arrays are applied to every item.
+//
+//	add, subtract, multiply, divide: number math
+//	round:   round to a number of decimals
+//	number:  parse a string as a number, `number: true`
+//	trim, upper, lower: string cleanup, `trim: true`
+//	regex:   first capture group, or the whole match, of a pattern
+//	replace: replace all `old` with `new`
+//	date:    parse with `from` and format with `to`, both go layouts or "unix"
+//	map:     lookup the value in a map of labels, `_` is used when missing
+//	default: value to use when missing or null
+//	filter:  keep array items matching a condition, `filter: value > 10`
+//	sort:    sort an array `asc` or `desc`
+//	limit:   keep the first number of items of an array
+//	reverse: reverse an array, `reverse: true`
+type Transform struct {
+	Op      string
+	Number  float64
+	Text    string
+	Pattern *regexp.Regexp
+	Replace TransformReplace
+	Date    TransformDate
+	Filter  Condition
+	Labels  map[string]interface{}
+	Value   interface{}
+}
+
+type TransformReplace struct {
+	Old string `yaml:"old"`
+	New string `yaml:"new"`
+}
+
+type TransformDate struct {
+	From string `yaml:"from,omitempty"`
+	To   string `yaml:"to,omitempty"`
+}
+
+func (t *Transform) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw map[string]interface{}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	if len(raw) != 1 {
+		var ops []string
+		for k := range raw {
+			ops = append(ops, k)
+		}
+		sort.Strings(ops)
+		return fmt.Errorf("transform must have exactly one operation, found: %v", strings.Join(ops, ", "))
+	}
+
+	for op, arg := range raw {
+		t.Op = op
+		t.Value = arg
+	}
+
+	var err error
+	switch t.Op {
+	case "add", "subtract", "multiply", "divide", "round", "limit":
+		t.Number, err = transformNumber(t.Value)
+		if err == nil && t.Op == "divide" && t.Number == 0 {
+			err = errors.New("can not divide by zero")
+		}
+		if err == nil && t.Op == "limit" && t.Number < 0 {
+			err = errors.New("limit can not be negative")
+		}
+	case "number", "trim", "upper", "lower", "reverse", "default":
+	case "regex":
+		t.Pattern, err = regexp.Compile(fmt.Sprint(t.Value))
+	case "sort":
+		t.Text = fmt.Sprint(t.Value)
+		if t.Text != "asc" && t.Text != "desc" {
+			err = fmt.Errorf("sort must be asc or desc, found '%v'", t.Text)
+		}
+	case "filter":
+		t.Filter, err = ParseCondition(fmt.Sprint(t.Value))
+	case "replace":
+		var args struct {
+			Replace TransformReplace `yaml:"replace"`
+		}
+		err = unmarshal(&args)
+		t.Replace = args.Replace
+	case "date":
+		var args struct {
+			Date TransformDate `yaml:"date"`
+		}
+		err = unmarshal(&args)
+		t.Date = args.Date
+		if t.Date.From == "" {
+			t.Date.From = time.RFC3339
+		}
+		if t.Date.To == "" {
+			t.Date.To = time.RFC3339
+		}
+	case "map":
+		var args struct {
+			Map map[string]interface{} `yaml:"map"`
+		}
+		err = unmarshal(&args)
+		t.Labels = args.Map
+	default:
+		err = fmt.Errorf("transform '%v' not found", t.Op)
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid transform '%v': %w", t.Op, err)
+	}
+	return nil
+}
+
+func transformNumber(value interface{}) (float64, error) {
+	if f, ok := ToFloat(value); ok {
+		return f, nil
+	}
+	return 0, fmt.Errorf("'%v' is not a number", value)
+}
@@ -0,0 +1,78 @@
+package configs
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestTransformUnmarshalYAML(t *testing.T) {
+	for _, tc := range []struct {
+		yaml  string
+		op    string
+		fails bool
+	}{
+		{yaml: "multiply: 1.8", op: "multiply"},
+		{yaml: "round: 2", op: "round"},
+		{yaml: "number: true", op: "number"},
+		{yaml: `regex: "(\\d+)%"`, op: "regex"},
+		{yaml: "sort: desc", op: "sort"},
+		{yaml: "filter: value > 10", op: "filter"},
+		{yaml: "replace: {old: a, new: b}", op: "replace"},
+		{yaml: "date: {from: unix}", op: "date"},
+		{yaml: "map: {up: online, _: offline}", op: "map"},
+		{yaml: "default: 0", op: "default"},
+		{yaml: "multiply: x", fails: true},
+		{yaml: "divide: 0", fails: true},
+		{yaml: "sort: up", fails: true},
+		{yaml: "limit: -1", fails: true},
+		{yaml: "regex: \"(\"", fails: true},
+		{yaml: "filter: value", fails: true},
+		{yaml: "explode: true", fails: true},
+		{yaml: "{add: 1, multiply: 2}", fails: true},
+		{yaml: "[add]", fails: true},
+	} {
+		t.Run(tc.yaml, func(t *testing.T) {
+			var transform Transform
+			err := yaml.Unmarshal([]byte(tc.yaml), &transform)
+			if tc.fails {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", transform)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if transform.Op != tc.op {
+				t.Errorf("expected op %v, got %v", tc.op, transform.Op)
+			}
+		})
+	}
+}
+
+func TestTransformUnmarshalYAMLArgs(t *testing.T) {
+	var transforms []Transform
+	err := yaml.Unmarshal([]byte(`
+- multiply: "1.8"
+- replace: {old: "-", new: " "}
+- date: {to: "2006-01-02"}
+- map: {up: online}
+`), &transforms)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if transforms[0].Number != 1.8 {
+		t.Errorf("expected number 1.8, got %v", transforms[0].Number)
+	}
+	if transforms[1].Replace != (TransformReplace{Old: "-", New: " "}) {
+		t.Errorf("expected replace - with space, got %+v", transforms[1].Replace)
+	}
+	if transforms[2].Date.From != "2006-01-02T15:04:05Z07:00" || transforms[2].Date.To != "2006-01-02" {
+		t.Errorf("expected date from RFC3339 to 2006-01-02, got %+v", transforms[2].Date)
+	}
+	if transforms[3].Labels["up"] != "online" {
+		t.Errorf("expected label online, got %v", transforms[3].Labels["up"])
+	}
+}
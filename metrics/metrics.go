@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "dashy"
+
+var (
+	FeedFetches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "feed_fetches_total",
+		Help:      "Number of feed fetches by result, either success or failure.",
+	}, []string{"feed", "result"})
+
+	FeedFetchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "feed_fetch_duration_seconds",
+		Help:      "Time taken to fetch a feed including all pages.",
+	}, []string{"feed"})
+
+	FeedResponses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "feed_responses_total",
+		Help:      "Number of upstream responses by HTTP status code.",
+	}, []string{"feed", "code"})
+
+	FeedLastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "feed_last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful feed update.",
+	}, []string{"feed"})
+
+	StoreDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "store_operation_duration_seconds",
+		Help:      "Time taken by store operations.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"operation"})
+
+	SSEClients = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sse_clients",
+		Help:      "Number of connected server sent event clients.",
+	})
+
+	HTTPDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to serve HTTP requests by handler.",
+	}, []string{"handler", "method", "code"})
+)
+
+// Handler serves all metrics in the prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// InstrumentHandler records request durations under the handler name
+func InstrumentHandler(name string, handler http.HandlerFunc) http.HandlerFunc {
+	return promhttp.InstrumentHandlerDuration(
+		HTTPDuration.MustCurryWith(prometheus.Labels{"handler": name}),
+		handler,
+	)
+}
+
+// ObserveStore starts timing a store operation, call the returned func when done:
+//
+//	defer metrics.ObserveStore("get_values")()
+func ObserveStore(operation string) func() {
+	start := time.Now()
+	return func() {
+		StoreDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+// Email sends the message through an SMTP server,
+// auth is only used when a username is provided.
+type Email struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+func (n *Email) Notify(msg Message) error {
+	port := n.Port
+	if port == 0 {
+		port = 25
+	}
+	addr := fmt.Sprintf("%v:%v", n.Host, port)
+
+	var auth smtp.Auth
+	if n.Username != "" {
+		auth = smtp.PlainAuth("", n.Username, n.Password, n.Host)
+	}
+
+	var body strings.Builder
+	body.WriteString(fmt.Sprintf("From: %v\r\n", n.From))
+	body.WriteString(fmt.Sprintf("To: %v\r\n", strings.Join(n.To, ", ")))
+	body.WriteString(fmt.Sprintf("Subject: %v\r\n", msg.Title))
+	body.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	body.WriteString("\r\n")
+	body.WriteString(msg.Text)
+	body.WriteString("\r\n")
+
+	if err := smtp.SendMail(addr, auth, n.From, n.To, []byte(body.String())); err != nil {
+		return fmt.Errorf("unable to send email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type smtpMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// newSMTPServer accepts a single mail over plain SMTP without auth
+func newSMTPServer(t *testing.T) (string, int, chan smtpMail) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	mails := make(chan smtpMail, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) {
+			_, _ = conn.Write([]byte(line + "\r\n"))
+		}
+
+		var mail smtpMail
+		reply("220 localhost ready")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSpace(line)
+			command := strings.ToUpper(line)
+
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				mail.from = strings.Trim(line[10:], "<>")
+				reply("250 ok")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				mail.to = append(mail.to, strings.Trim(line[8:], "<>"))
+				reply("250 ok")
+			case command == "DATA":
+				reply("354 send data")
+				var data strings.Builder
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				mail.data = data.String()
+				reply("250 ok")
+			case command == "QUIT":
+				reply("221 bye")
+				mails <- mail
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	return host, portNumber, mails
+}
+
+func TestEmailNotify(t *testing.T) {
+	host, port, mails := newSMTPServer(t)
+
+	email := &Email{
+		Host: host,
+		Port: port,
+		From: "dashy@example.com",
+		To:   []string{"ops@example.com", "dev@example.com"},
+	}
+	if err := email.Notify(testMessage); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mail := <-mails
+	if mail.from != "dashy@example.com" {
+		t.Errorf("expected from 'dashy@example.com', got '%v'", mail.from)
+	}
+	if strings.Join(mail.to, ",") != "ops@example.com,dev@example.com" {
+		t.Errorf("unexpected recipients: %v", mail.to)
+	}
+	for _, expected := range []string{
+		"Subject: cpu is firing\r\n",
+		"To: ops@example.com, dev@example.com\r\n",
+		"\r\n\r\ncpu is 95\r\n",
+	} {
+		if !strings.Contains(mail.data, expected) {
+			t.Errorf("expected data to contain %q, got %q", expected, mail.data)
+		}
+	}
+}
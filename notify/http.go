@@ -0,0 +1,97 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Webhook posts the message as JSON to any url.
+type Webhook struct {
+	Url     string
+	Headers map[string]string
+	Client  *http.Client
+}
+
+func (n *Webhook) Notify(msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	for k, v := range n.Headers {
+		headers[k] = v
+	}
+
+	return post(n.Client, n.Url, headers, body)
+}
+
+// Slack posts a message to a slack compatible incoming webhook.
+type Slack struct {
+	Url    string
+	Client *http.Client
+}
+
+func (n *Slack) Notify(msg Message) error {
+	body, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("*%v*\n%v", msg.Title, msg.Text),
+	})
+	if err != nil {
+		return err
+	}
+
+	return post(n.Client, n.Url, map[string]string{"Content-Type": "application/json"}, body)
+}
+
+// Ntfy publishes a push notification to an ntfy style topic url.
+type Ntfy struct {
+	Url      string
+	Priority string
+	Headers  map[string]string
+	Client   *http.Client
+}
+
+func (n *Ntfy) Notify(msg Message) error {
+	headers := map[string]string{
+		"Title": msg.Title,
+		"Tags":  "warning",
+	}
+	if !msg.Firing {
+		headers["Tags"] = "white_check_mark"
+	}
+	if n.Priority != "" {
+		headers["Priority"] = n.Priority
+	}
+	for k, v := range n.Headers {
+		headers[k] = v
+	}
+
+	return post(n.Client, n.Url, headers, []byte(msg.Text))
+}
+
+func post(client *http.Client, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send notification: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("notification returned status code '%v'", res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type capturedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newCaptureServer(t *testing.T, status int) (*httptest.Server, chan capturedRequest) {
+	t.Helper()
+
+	requests := make(chan capturedRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- capturedRequest{header: r.Header, body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
+
+var testMessage = Message{
+	Alert:  "cpu",
+	Title:  "cpu is firing",
+	Text:   "cpu is 95",
+	Value:  95.0,
+	Firing: true,
+	At:     time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+}
+
+func TestWebhookNotify(t *testing.T) {
+	server, requests := newCaptureServer(t, http.StatusOK)
+
+	webhook := &Webhook{
+		Url:     server.URL,
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		Client:  server.Client(),
+	}
+	if err := webhook.Notify(testMessage); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := <-requests
+	if got := req.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected json content type, got '%v'", got)
+	}
+	if got := req.header.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("expected authorization header, got '%v'", got)
+	}
+
+	var msg Message
+	if err := json.Unmarshal(req.body, &msg); err != nil {
+		t.Fatalf("unable to unmarshal body: %v", err)
+	}
+	if msg.Alert != "cpu" || msg.Value != 95.0 || !msg.Firing || !msg.At.Equal(testMessage.At) {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+}
+
+func TestSlackNotify(t *testing.T) {
+	server, requests := newCaptureServer(t, http.StatusOK)
+
+	slack := &Slack{Url: server.URL, Client: server.Client()}
+	if err := slack.Notify(testMessage); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal((<-requests).body, &body); err != nil {
+		t.Fatalf("unable to unmarshal body: %v", err)
+	}
+	if expected := "*cpu is firing*\ncpu is 95"; body["text"] != expected {
+		t.Errorf("expected text '%v', got '%v'", expected, body["text"])
+	}
+}
+
+func TestNtfyNotify(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		firing   bool
+		priority string
+		tags     string
+	}{
+		{name: "firing", firing: true, priority: "high", tags: "warning"},
+		{name: "recovered", firing: false, tags: "white_check_mark"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server, requests := newCaptureServer(t, http.StatusOK)
+
+			msg := testMessage
+			msg.Firing = tc.firing
+
+			ntfy := &Ntfy{Url: server.URL, Priority: tc.priority, Client: server.Client()}
+			if err := ntfy.Notify(msg); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			req := <-requests
+			if string(req.body) != msg.Text {
+				t.Errorf("expected body '%v', got '%v'", msg.Text, string(req.body))
+			}
+			if got := req.header.Get("Title"); got != msg.Title {
+				t.Errorf("expected title '%v', got '%v'", msg.Title, got)
+			}
+			if got := req.header.Get("Tags"); got != tc.tags {
+				t.Errorf("expected tags '%v', got '%v'", tc.tags, got)
+			}
+			if got := req.header.Get("Priority"); got != tc.priority {
+				t.Errorf("expected priority '%v', got '%v'", tc.priority, got)
+			}
+		})
+	}
+}
+
+func TestNotifyErrorStatus(t *testing.T) {
+	server, _ := newCaptureServer(t, http.StatusInternalServerError)
+
+	webhook := &Webhook{Url: server.URL, Client: server.Client()}
+	if err := webhook.Notify(testMessage); err == nil {
+		t.Error("expected an error for a 500 response")
+	}
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+// Message is a single alert notification, either firing or recovered.
+type Message struct {
+	Alert  string      `json:"alert"`
+	Title  string      `json:"title"`
+	Text   string      `json:"text"`
+	Value  interface{} `json:"value"`
+	Firing bool        `json:"firing"`
+	At     time.Time   `json:"at"`
+}
+
+type Notifier interface {
+	Notify(msg Message) error
+}
+
+// Resolver replaces config values with secrets, such as Store.StringOrVar
+type Resolver func(value string) string
+
+// NewNotifier builds a notifier from config, any secret values such as
+// urls, headers and passwords are passed through resolve first.
+func NewNotifier(config configs.NotifierConfig, resolve Resolver) (Notifier, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	headers := make(map[string]string, len(config.Headers))
+	for k, v := range config.Headers {
+		headers[k] = resolve(v)
+	}
+
+	switch strings.ToLower(config.Type) {
+	case "webhook":
+		return &Webhook{
+			Url:     resolve(config.Url),
+			Headers: headers,
+			Client:  client,
+		}, nil
+	case "slack":
+		return &Slack{
+			Url:    resolve(config.Url),
+			Client: client,
+		}, nil
+	case "ntfy":
+		return &Ntfy{
+			Url:      resolve(config.Url),
+			Priority: config.Priority,
+			Headers:  headers,
+			Client:   client,
+		}, nil
+	case "email":
+		return &Email{
+			Host:     config.Host,
+			Port:     config.Port,
+			Username: resolve(config.Username),
+			Password: resolve(config.Password),
+			From:     config.From,
+			To:       config.To,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("notifier type '%v' not found", config.Type)
+}
@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+func TestNewNotifier(t *testing.T) {
+	resolve := func(value string) string {
+		return strings.ReplaceAll(value, "env:SECRET", "resolved")
+	}
+
+	for _, tc := range []struct {
+		config   configs.NotifierConfig
+		expected string
+	}{
+		{config: configs.NotifierConfig{Type: "webhook", Url: "http://x/env:SECRET"}, expected: "http://x/resolved"},
+		{config: configs.NotifierConfig{Type: "Slack", Url: "env:SECRET"}, expected: "resolved"},
+		{config: configs.NotifierConfig{Type: "ntfy", Url: "http://ntfy/topic"}, expected: "http://ntfy/topic"},
+		{config: configs.NotifierConfig{Type: "email", Password: "env:SECRET"}, expected: "resolved"},
+	} {
+		t.Run(tc.config.Type, func(t *testing.T) {
+			notifier, err := NewNotifier(tc.config, resolve)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got string
+			switch n := notifier.(type) {
+			case *Webhook:
+				got = n.Url
+			case *Slack:
+				got = n.Url
+			case *Ntfy:
+				got = n.Url
+			case *Email:
+				got = n.Password
+			}
+			if got != tc.expected {
+				t.Errorf("expected '%v', got '%v'", tc.expected, got)
+			}
+		})
+	}
+
+	if _, err := NewNotifier(configs.NotifierConfig{Type: "pager"}, resolve); err == nil {
+		t.Error("expected an error for an unknown type")
+	}
+}
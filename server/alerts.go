@@ -0,0 +1,141 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/miniscruff/dashy/configs"
+	"github.com/miniscruff/dashy/notify"
+)
+
+func (s *Server) buildNotifiers() error {
+	s.notifiers = make(map[string]notify.Notifier, len(s.Config.Notifiers))
+	for _, n := range s.Config.Notifiers {
+		notifier, err := notify.NewNotifier(n, s.Store.StringOrVar)
+		if err != nil {
+			return fmt.Errorf("unable to create notifier '%v': %w", n.Name, err)
+		}
+		s.notifiers[n.Name] = notifier
+	}
+	return nil
+}
+
+// CheckAlerts evaluates all alerts watching a feed against the latest values.
+func (s *Server) CheckAlerts(feed *configs.FeedConfig) {
+	alerts := s.Config.AlertsByFeed(feed.Name)
+	if len(alerts) == 0 {
+		return
+	}
+
+	values, err := s.Store.GetValues()
+	if err != nil {
+		log.Println(fmt.Errorf("unable to get values for alerts: %w", err))
+		return
+	}
+
+	for _, a := range alerts {
+		err := s.checkAlert(&a, values[a.Feed()][a.Store()])
+		if err != nil {
+			log.Println(fmt.Errorf("unable to check alert '%v': %w", a.Name, err))
+		}
+	}
+}
+
+func (s *Server) checkAlert(alert *configs.AlertConfig, value interface{}) error {
+	state, err := s.Store.GetAlertState(alert.Name)
+	if err != nil {
+		return fmt.Errorf("unable to get alert state: %w", err)
+	}
+
+	firing := alert.When.Matches(value)
+	if !firing && !state.Firing && len(state.Failed) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	send := false
+	switch {
+	case firing && !state.Firing:
+		send = true
+	case firing && state.Firing:
+		send = resendDue(alert, state.LastSent, now)
+	case !firing && state.Firing:
+		send = alert.NotifyRecovery
+	}
+
+	var notifiers []string
+	if send {
+		notifiers = alert.Notify
+		state.LastSent = now
+	} else if firing == state.Firing {
+		// retry the same message to only the notifiers that failed
+		notifiers = state.Failed
+	}
+
+	state.Firing = firing
+	state.Failed = s.sendAlert(alert, value, firing, now, notifiers)
+	if err := s.Store.SetAlertState(alert.Name, state); err != nil {
+		return err
+	}
+
+	if len(state.Failed) > 0 {
+		return fmt.Errorf("unable to notify %v", strings.Join(state.Failed, ", "))
+	}
+	return nil
+}
+
+func resendDue(alert *configs.AlertConfig, lastSent, now time.Time) bool {
+	if alert.ResendEvery == "" {
+		return false
+	}
+	return now.Sub(lastSent) >= alert.ResendEveryDuration
+}
+
+// sendAlert notifies each of the notifiers, returning the names of those that failed
+func (s *Server) sendAlert(
+	alert *configs.AlertConfig,
+	value interface{},
+	firing bool,
+	now time.Time,
+	notifiers []string,
+) []string {
+	if len(notifiers) == 0 {
+		return nil
+	}
+
+	msg := notify.Message{
+		Alert:  alert.Name,
+		Value:  value,
+		Firing: firing,
+		At:     now,
+	}
+
+	if firing {
+		msg.Title = fmt.Sprintf("[FIRING] %v", alert.Name)
+		msg.Text = fmt.Sprintf("%v is %v, matched %v", alert.Value, value, alert.When)
+	} else {
+		msg.Title = fmt.Sprintf("[RESOLVED] %v", alert.Name)
+		msg.Text = fmt.Sprintf("%v is %v, no longer matches %v", alert.Value, value, alert.When)
+	}
+
+	if alert.Message != "" {
+		msg.Text = alert.Message + "\n" + msg.Text
+	}
+
+	var failed []string
+	for _, name := range notifiers {
+		notifier, exists := s.notifiers[name]
+		if !exists {
+			continue
+		}
+
+		log.Printf("sending alert '%v' to '%v'\n", alert.Name, name)
+		if err := notifier.Notify(msg); err != nil {
+			log.Println(fmt.Errorf("unable to notify '%v': %w", name, err))
+			failed = append(failed, name)
+		}
+	}
+	return failed
+}
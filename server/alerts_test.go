@@ -0,0 +1,83 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/miniscruff/dashy/configs"
+	"github.com/miniscruff/dashy/notify"
+)
+
+type fakeNotifier struct {
+	fail     bool
+	messages []notify.Message
+}
+
+func (n *fakeNotifier) Notify(msg notify.Message) error {
+	if n.fail {
+		return errors.New("unavailable")
+	}
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+func TestCheckAlertRetriesFailedNotifiers(t *testing.T) {
+	when, err := configs.ParseCondition("value > 90")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alert := &configs.AlertConfig{
+		Name:           "cpu",
+		Value:          "server.cpu",
+		When:           when,
+		NotifyRecovery: true,
+		Notify:         []string{"chat", "email"},
+	}
+
+	chat := &fakeNotifier{}
+	email := &fakeNotifier{fail: true}
+	s := &Server{
+		Store:     newMemoryStore(),
+		notifiers: map[string]notify.Notifier{"chat": chat, "email": email},
+	}
+
+	if err := s.checkAlert(alert, 95.0); err == nil {
+		t.Fatal("expected an error for the failed notifier")
+	}
+	state, _ := s.Store.GetAlertState("cpu")
+	if !state.Firing || state.LastSent.IsZero() {
+		t.Errorf("expected the alert to be saved as firing, got %+v", state)
+	}
+	if len(state.Failed) != 1 || state.Failed[0] != "email" {
+		t.Errorf("expected email to be failed, got %v", state.Failed)
+	}
+
+	email.fail = false
+	if err := s.checkAlert(alert, 96.0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chat.messages) != 1 {
+		t.Errorf("expected chat to be sent 1 message, got %v", len(chat.messages))
+	}
+	if len(email.messages) != 1 || !email.messages[0].Firing {
+		t.Errorf("expected email to be retried with the firing message, got %+v", email.messages)
+	}
+
+	if err := s.checkAlert(alert, 97.0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chat.messages) != 1 || len(email.messages) != 1 {
+		t.Errorf("expected no more messages while firing, got %v and %v", len(chat.messages), len(email.messages))
+	}
+
+	if err := s.checkAlert(alert, 10.0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chat.messages) != 2 || chat.messages[1].Firing {
+		t.Errorf("expected a recovery message, got %+v", chat.messages)
+	}
+	state, _ = s.Store.GetAlertState("cpu")
+	if state.Firing || len(state.Failed) != 0 {
+		t.Errorf("expected the alert to be resolved, got %+v", state)
+	}
+}
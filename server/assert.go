@@ -0,0 +1,110 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/tidwall/gjson"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+// assertionError reports the json path that failed an assertion
+type assertionError struct {
+	Path    string
+	Message string
+}
+
+func (e *assertionError) Error() string {
+	return fmt.Sprintf("assertion failed at '%v': %v", e.Path, e.Message)
+}
+
+// schemaCache compiles feed schemas once, the zero value is ready to use.
+type schemaCache struct {
+	mu      sync.Mutex
+	schemas map[string]*gojsonschema.Schema
+}
+
+func (c *schemaCache) schema(feed *configs.FeedConfig) (*gojsonschema.Schema, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if schema, exists := c.schemas[feed.Name]; exists {
+		return schema, nil
+	}
+
+	schema, err := feed.Assert.CompileSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.schemas == nil {
+		c.schemas = make(map[string]*gojsonschema.Schema)
+	}
+	c.schemas[feed.Name] = schema
+	return schema, nil
+}
+
+func (s *Server) assert(feed *configs.FeedConfig, body []byte) error {
+	if feed.Assert == nil {
+		return nil
+	}
+
+	for _, path := range feed.Assert.Required {
+		if !gjson.GetBytes(body, path).Exists() {
+			return &assertionError{Path: path, Message: "required path is missing"}
+		}
+	}
+
+	for path, expected := range feed.Assert.Types {
+		result := gjson.GetBytes(body, path)
+		if actual := jsonType(result); actual != expected {
+			return &assertionError{
+				Path:    path,
+				Message: fmt.Sprintf("expected %v but got %v", expected, actual),
+			}
+		}
+	}
+
+	if len(feed.Assert.Schema) > 0 {
+		schema, err := s.schemas.schema(feed)
+		if err != nil {
+			return err
+		}
+
+		result, err := schema.Validate(gojsonschema.NewBytesLoader(body))
+		if err != nil {
+			return fmt.Errorf("unable to validate schema: %w", err)
+		}
+
+		if !result.Valid() {
+			first := result.Errors()[0]
+			return &assertionError{Path: first.Field(), Message: first.Description()}
+		}
+	}
+
+	return nil
+}
+
+func jsonType(result gjson.Result) string {
+	if !result.Exists() {
+		return "missing"
+	}
+
+	switch result.Type {
+	case gjson.String:
+		return "string"
+	case gjson.Number:
+		return "number"
+	case gjson.True, gjson.False:
+		return "bool"
+	case gjson.Null:
+		return "null"
+	}
+
+	if result.IsArray() {
+		return "array"
+	}
+	return "object"
+}
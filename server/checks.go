@@ -46,6 +46,10 @@ func (s *Server) CheckAllFeeds() {
 }
 
 func (s *Server) CheckFeed(feed *configs.FeedConfig) error {
+	if feed.IsPush() {
+		return nil
+	}
+
 	log.Printf("checking feed: %v\n", feed.Name)
 
 	needsUpdate, err := s.feedOutOfDate(feed)
@@ -58,6 +62,11 @@ func (s *Server) CheckFeed(feed *configs.FeedConfig) error {
 		return nil
 	}
 
+	if host := feedHost(feed); !s.limits.Available(host) {
+		log.Printf("feed deferred, rate limit reached for '%v': %v\n", host, feed.Name)
+		return nil
+	}
+
 	s.UpdateFeed(feed)
 	return nil
 }
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+const defaultCommandTimeout = 30 * time.Second
+
+func (s *Server) fetchCommand(feed *configs.FeedConfig) ([]byte, error) {
+	command := feed.Command
+	tmpl := s.newQueryTemplate(feed)
+
+	args := make([]string, len(command.Run))
+	for i, arg := range command.Run {
+		rendered, err := tmpl.Render("run", arg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render command argument: %w", err)
+		}
+		args[i] = rendered
+	}
+
+	timeout := defaultCommandTimeout
+	if command.TimeoutDuration > 0 {
+		timeout = command.TimeoutDuration
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = command.Dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = os.Environ()
+	for k, v := range command.Env {
+		rendered, err := tmpl.Render(k, v)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render env '%v': %w", k, err)
+		}
+		cmd.Env = append(cmd.Env, k+"="+s.Store.StringOrVar(rendered))
+	}
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("command timed out after %v", timeout)
+		}
+		return nil, fmt.Errorf("command failed: %w: %v", err, strings.TrimSpace(stderr.String()))
+	}
+
+	if command.Format == "text" {
+		return textDocument(stdout.String())
+	}
+
+	if !gjson.ValidBytes(stdout.Bytes()) {
+		return nil, errors.New("command output is not a valid JSON")
+	}
+	return stdout.Bytes(), nil
+}
+
+func textDocument(output string) ([]byte, error) {
+	lines := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"text":  strings.TrimSpace(output),
+		"lines": lines,
+	})
+}
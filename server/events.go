@@ -1,9 +1,96 @@
 package server
 
 import (
+	"fmt"
+	"log"
 	"net/http"
+	"sync"
+
+	"github.com/miniscruff/dashy/metrics"
 )
 
+// eventHub fans out server sent events to every connected client,
+// the zero value is ready to use.
+type eventHub struct {
+	mu      sync.Mutex
+	clients map[chan []byte]struct{}
+}
+
+func (h *eventHub) subscribe() chan []byte {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.clients == nil {
+		h.clients = make(map[chan []byte]struct{})
+	}
+
+	ch := make(chan []byte, 1)
+	h.clients[ch] = struct{}{}
+	return ch
+}
+
+func (h *eventHub) unsubscribe(ch chan []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients, ch)
+}
+
+func (h *eventHub) broadcast(data []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.clients {
+		// slow clients skip a message instead of blocking updates,
+		// every message contains all values so nothing is lost
+		select {
+		case ch <- data:
+		default:
+		}
+	}
+}
+
+// PublishValues sends the latest values to all connected dashboards
+func (s *Server) PublishValues() {
+	jsonData, err := s.valuesJson(true)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	s.events.broadcast(jsonData)
+}
+
 func (s *Server) EventsHandler(w http.ResponseWriter, r *http.Request) {
-	// server sent events
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ch := s.events.subscribe()
+	defer s.events.unsubscribe(ch)
+
+	metrics.SSEClients.Inc()
+	defer metrics.SSEClients.Dec()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data := <-ch:
+			fmt.Fprintf(w, "event: values\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
 }
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+func (s *Server) graphqlBody(tmpl *queryTemplate, gql *configs.FeedGraphQL) (string, error) {
+	query, err := tmpl.Render("graphql", gql.Query)
+	if err != nil {
+		return "", fmt.Errorf("unable to render graphql query: %w", err)
+	}
+
+	variables := make(map[string]interface{}, len(gql.Variables))
+	for k, v := range gql.Variables {
+		str, ok := v.(string)
+		if !ok {
+			variables[k] = v
+			continue
+		}
+
+		rendered, err := tmpl.Render(k, str)
+		if err != nil {
+			return "", fmt.Errorf("unable to render graphql variable '%v': %w", k, err)
+		}
+		variables[k] = s.Store.StringOrVar(rendered)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	return string(body), err
+}
+
+// graphqlData returns the data of a graphql response,
+// any errors in the response are treated as a failed fetch.
+func graphqlData(body []byte) ([]byte, error) {
+	errs := gjson.GetBytes(body, "errors")
+	if errs.IsArray() && len(errs.Array()) > 0 {
+		var messages []string
+		for _, e := range errs.Array() {
+			messages = append(messages, e.Get("message").String())
+		}
+		return nil, fmt.Errorf("graphql errors: %v", strings.Join(messages, "; "))
+	}
+
+	data := gjson.GetBytes(body, "data")
+	if !data.Exists() || data.Type == gjson.Null {
+		return nil, errors.New("graphql response is missing data")
+	}
+
+	return []byte(data.Raw), nil
+}
@@ -2,11 +2,13 @@ package server
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	textTemplate "text/template"
 	// htmlTemplate "html/template"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/miniscruff/dashy/configs"
@@ -15,24 +17,6 @@ import (
 type ContentBuilder func(configs.Content) (string, error)
 
 var defaultStyles = map[string]string{
-	":root": `
-  /* nord color scheme */
-  --layer0: #2e3440;
-  --layer1: #3b4252;
-  --layer2: #434c5e;
-  --layer3: #4c566a;
-  --primary2: #d8dee9;
-  --primary1: #e5e9f0;
-  --primary: #eceff4;
-  --accent1: #8fbcbb;
-  --accent: #88c0d0;
-  --accent2: #81a1c1;
-  --accent3: #5e81ac;
-  --error: #bf616a;
-  --danger: #d08770;
-  --warning: #ebcb8b;
-  --success: #a3be8c;
-  --neutral: #b48ead;`,
 	".text-left":   "text-align: left;",
 	".text-center": "text-align: center;",
 	".text-right":  "text-align: right;",
@@ -41,10 +25,7 @@ var defaultStyles = map[string]string{
 	"body": `
 	background: var(--layer0);
 	color: var(--primary);
-  padding: 1em;
-  display: grid;
-  grid-template-columns: 1fr 1fr 1fr;
-  grid-gap: .25rem;`,
+  padding: 1em;`,
 	".layer": `
 	background: var(--layer1);
 	border-radius: 10px;`,
@@ -52,6 +33,53 @@ var defaultStyles = map[string]string{
 	display: grid;
 	grid-template-columns: 1fr 1fr;
 	grid-column-gap: 10px;`,
+	".badge": `
+	display: inline-block;
+	padding: 0 .5em;
+	border-radius: 1em;
+	color: var(--layer0);
+	background: var(--primary2);`,
+	".error":         "color: var(--error);",
+	".danger":        "color: var(--danger);",
+	".warning":       "color: var(--warning);",
+	".success":       "color: var(--success);",
+	".neutral":       "color: var(--neutral);",
+	".badge.error":   "color: var(--layer0); background: var(--error);",
+	".badge.danger":  "color: var(--layer0); background: var(--danger);",
+	".badge.warning": "color: var(--layer0); background: var(--warning);",
+	".badge.success": "color: var(--layer0); background: var(--success);",
+	".badge.neutral": "color: var(--layer0); background: var(--neutral);",
+	".age":           "display: block; font-size: small; color: var(--primary2);",
+	".stale":         "opacity: .5;",
+	".layer-header": `
+	display: flex;
+	align-items: center;
+	gap: .5em;
+	padding: .25em .5em;
+	font-weight: bold;
+	border-bottom: 1px solid var(--layer2);`,
+	".layer-header a":    "color: inherit;",
+	".layer-header .age": "display: inline; margin-left: auto; font-weight: normal;",
+	".layer-icon":        "height: 1em;",
+	".layer-grid":        "padding: .25rem;",
+	".layer .layer":      "background: var(--layer2);",
+	"nav": `
+	display: flex;
+	gap: 1em;
+	margin-bottom: 1em;`,
+	"nav a":        "color: var(--primary2); text-decoration: none;",
+	"nav a.active": "color: var(--accent); font-weight: bold;",
+	".kiosk nav":   "display: none;",
+	".theme-toggle": `
+	position: fixed;
+	top: .5em;
+	right: .5em;
+	background: var(--layer2);
+	color: var(--primary);
+	border: none;
+	border-radius: 1em;
+	cursor: pointer;`,
+	".kiosk .theme-toggle": "display: none;",
 }
 
 var (
@@ -66,12 +94,62 @@ var (
 		</style>
 	</head>
 	<body>
-		{{.body}}
+		{{.toggle}}
+		{{.nav}}
+		<main>{{.body}}</main>
 		<script>
 			{{.scripts}}
 		</script>
 	</body>
 </html>`
+
+	// ageScripts are shared by content showing the age of values
+	ageScripts = `
+	function latestOf(times) {
+		return times ? Object.values(times).sort().pop() : null;
+	}
+	function ageOf(updatedAt) {
+		return updatedAt ? Date.now() - Date.parse(updatedAt) : null;
+	}
+	function formatAge(age) {
+		if (age === null) {
+			return "never updated";
+		}
+		const seconds = Math.max(0, Math.floor(age / 1000));
+		const units = [["d", 86400], ["h", 3600], ["m", 60]];
+		for (const [unit, size] of units) {
+			if (seconds >= size) {
+				return "updated " + Math.floor(seconds / size) + unit + " ago";
+			}
+		}
+		return "updated just now";
+	}
+	`
+
+	// conditionScripts parse values the same way as Condition.Matches,
+	// null when the value is not a number or bool.
+	conditionScripts = `
+	function numberOf(value) {
+		if (typeof value === "number") {
+			return value;
+		}
+		if (typeof value === "string" && value.trim() !== "" && !isNaN(Number(value))) {
+			return Number(value);
+		}
+		return null;
+	}
+	const bools = {
+		"1": true, "t": true, "T": true, "TRUE": true, "true": true, "True": true,
+		"0": false, "f": false, "F": false, "FALSE": false, "false": false, "False": false,
+	};
+	function boolOf(value) {
+		if (typeof value === "boolean") {
+			return value;
+		}
+		const b = typeof value === "string" ? bools[value.trim()] : undefined;
+		return b === undefined ? null : b;
+	}
+	`
 )
 
 const (
@@ -89,67 +167,362 @@ func stringFromIndex(index *int) string {
 }
 
 type IndexBuilder struct {
-	dashboard    configs.Dashboard
+	dashboard configs.Dashboard
+	feeds     []configs.FeedConfig
+	// path is where this dashboard is served, pages are all dashboards for the nav
+	path  string
+	pages []dashboardPage
+	kiosk *configs.KioskConfig
+	theme themeColors
+	// styleFiles are the contents of the dashboard style files
+	styleFiles   []string
 	elements     map[string]string
 	contentIndex int
+	// grids are the body grid and the grid of every layer with child layers
+	grids []*layerGrid
+}
+
+// layerGrid is a grid and the layers placed on it by element id
+type layerGrid struct {
+	selector   string
+	grid       configs.Grid
+	placements []layerPlacement
+}
+
+type dashboardPage struct {
+	path  string
+	title string
+}
+
+type layerPlacement struct {
+	id    string
+	layer configs.Layer
 }
 
 func (b *IndexBuilder) buildMeta() (string, error) {
 	var builder strings.Builder
 
+	names := make([]string, 0, len(b.dashboard.Meta))
+	for n := range b.dashboard.Meta {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
 	metaFormat := `<meta name="%v" content="%v" />`
-	for n, c := range b.dashboard.Meta {
-		_, _ = builder.WriteString(fmt.Sprintf(metaFormat, n, c))
+	for _, n := range names {
+		_, _ = builder.WriteString(fmt.Sprintf(metaFormat, n, b.dashboard.Meta[n]))
+	}
+	return builder.String(), nil
+}
+
+// buildNav links to every dashboard, it is skipped with only one dashboard
+func (b *IndexBuilder) buildNav() (string, error) {
+	if len(b.pages) < 2 {
+		return "", nil
 	}
+
+	var builder strings.Builder
+	builder.WriteString("<nav>")
+	for _, p := range b.pages {
+		class := ""
+		if p.path == b.path {
+			class = "active"
+		}
+		builder.WriteString(fmt.Sprintf(`<a href="%v" class="%v">%v</a>`, p.path, class, p.title))
+	}
+	builder.WriteString("</nav>")
 	return builder.String(), nil
 }
 
 func (b *IndexBuilder) buildLayers() (string, error) {
+	return b.buildGridLayers("main", b.dashboard.Grid, b.dashboard.Layers)
+}
+
+// buildGridLayers builds layers placed on a grid, the grid styles
+// are created later with the rest of the styles.
+func (b *IndexBuilder) buildGridLayers(selector string, grid configs.Grid, layers []configs.Layer) (string, error) {
+	lg := &layerGrid{selector: selector, grid: grid}
+	b.grids = append(b.grids, lg)
+
 	var builder strings.Builder
-	for _, l := range b.dashboard.Layers {
-		layer, err := b.buildLayer(l)
-		if err != nil {
-			return "", err
+	for _, l := range layers {
+		expanded := []configs.Layer{l}
+		if l.ForEach != "" {
+			var err error
+			if expanded, err = b.expandLayer(l); err != nil {
+				return "", err
+			}
 		}
 
-		_, _ = builder.WriteString(layer)
+		for _, el := range expanded {
+			layer, err := b.buildLayer(el, lg)
+			if err != nil {
+				return "", err
+			}
+
+			_, _ = builder.WriteString(layer)
+		}
 	}
 	return builder.String(), nil
 }
 
-func (b *IndexBuilder) buildLayer(layer configs.Layer) (string, error) {
-	var contentBuilder strings.Builder
-	for _, c := range layer.Contents {
-		content, err := b.buildContent(c)
+// expandLayer creates a copy of the layer for each feed in its forEach group,
+// the layer text, contents and child layers are templated with the feed vars
+// and `{{.feed}}` as the expanded feed name.
+func (b *IndexBuilder) expandLayer(layer configs.Layer) ([]configs.Layer, error) {
+	var layers []configs.Layer
+	for _, f := range b.feeds {
+		if f.Group != layer.ForEach {
+			continue
+		}
+
+		vars := map[string]string{"feed": f.Name}
+		for k, v := range f.Vars {
+			vars[k] = v
+		}
+
+		instance, err := renderLayer(layer, vars)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
-		_, _ = contentBuilder.WriteString(content)
+		layers = append(layers, instance)
 	}
+	return layers, nil
+}
 
-	// TODO:
-	// we need to create an extra layer here for the optional title bar
-	// additionally, this may need to be recursive to allow layers on layers
-	// not sure entirely how that one will work yet.
+func renderLayer(layer configs.Layer, vars map[string]string) (configs.Layer, error) {
+	instance := layer
+	instance.Contents = make([]configs.Content, len(layer.Contents))
+	instance.Layers = make([]configs.Layer, len(layer.Layers))
+
+	var err error
+	for _, text := range []*string{
+		&instance.Name,
+		&instance.Title,
+		&instance.Icon,
+		&instance.Link,
+		&instance.Updated,
+		&instance.Css,
+	} {
+		if *text, err = renderLayerText(*text, vars); err != nil {
+			return instance, err
+		}
+	}
+
+	for i, c := range layer.Contents {
+		if c.Text, err = renderLayerText(c.Text, vars); err != nil {
+			return instance, err
+		}
+		if c.Value, err = renderLayerText(c.Value, vars); err != nil {
+			return instance, err
+		}
+		if c.Css, err = renderLayerText(c.Css, vars); err != nil {
+			return instance, err
+		}
+		instance.Contents[i] = c
+	}
+
+	for i, child := range layer.Layers {
+		if instance.Layers[i], err = renderLayer(child, vars); err != nil {
+			return instance, err
+		}
+	}
+
+	return instance, nil
+}
+
+func renderLayerText(text string, vars map[string]string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := textTemplate.New("layer").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, vars); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+func gridStyle(layer configs.Layer) string {
+	// forEach layers are repeated so they flow into the grid instead
+	if layer.ForEach != "" {
+		return fmt.Sprintf(
+			"grid-column: span %v;grid-row: span %v;",
+			layer.Width,
+			layer.Height,
+		)
+	}
 
 	return fmt.Sprintf(
-		`<div id="%v" class="layer %v" style="grid-column-start: %v;grid-column-end: %v;grid-row-start: %v;grid-row-end: %v;">%v</div>`,
-		stringFromIndex(&b.contentIndex),
-		layer.Layout,
+		"grid-column: %v / span %v;grid-row: %v / span %v;",
 		layer.X+1,
-		layer.X+layer.Width,
+		layer.Width,
 		layer.Y+1,
-		layer.Y+layer.Height,
-		contentBuilder.String(),
+		layer.Height,
+	)
+}
+
+// collapsedGridStyle flows a layer into a grid with fewer columns,
+// keeping its size but never spanning more columns than the grid has.
+func collapsedGridStyle(layer configs.Layer, columns int) string {
+	width := layer.Width
+	if width > columns {
+		width = columns
+	}
+
+	return fmt.Sprintf(
+		"grid-column: auto / span %v;grid-row: auto / span %v;",
+		width,
+		layer.Height,
+	)
+}
+
+// buildGrid creates the styles of every grid and its layer placements
+// including any responsive breakpoints.
+func (b *IndexBuilder) buildGrid() string {
+	var builder strings.Builder
+
+	for _, lg := range b.grids {
+		builder.WriteString(fmt.Sprintf(
+			"%v {display: grid;grid-template-columns: repeat(%v, 1fr);grid-gap: %v;",
+			lg.selector,
+			lg.grid.ColumnCount(),
+			lg.grid.GapSize(),
+		))
+		if lg.grid.RowHeight != "" {
+			builder.WriteString(fmt.Sprintf("grid-auto-rows: %v;", lg.grid.RowHeight))
+		}
+		builder.WriteString("}")
+
+		for _, p := range lg.placements {
+			builder.WriteString(fmt.Sprintf("#%v {%v}", p.id, gridStyle(p.layer)))
+		}
+	}
+
+	// breakpoints are written after every placement so they take priority
+	for _, lg := range b.grids {
+		for _, bp := range lg.grid.Breakpoints {
+			builder.WriteString(fmt.Sprintf("@media (max-width: %v) {", bp.MaxWidth))
+			builder.WriteString(fmt.Sprintf(
+				"%v {grid-template-columns: repeat(%v, 1fr);}",
+				lg.selector,
+				bp.Columns,
+			))
+			for _, p := range lg.placements {
+				builder.WriteString(fmt.Sprintf(
+					"#%v {%v}",
+					p.id,
+					collapsedGridStyle(p.layer, bp.Columns),
+				))
+			}
+			builder.WriteString("}")
+		}
+	}
+
+	return builder.String()
+}
+
+func (b *IndexBuilder) buildLayer(layer configs.Layer, lg *layerGrid) (string, error) {
+	id := stringFromIndex(&b.contentIndex)
+	lg.placements = append(lg.placements, layerPlacement{id: id, layer: layer})
+
+	var builder strings.Builder
+	_, _ = builder.WriteString(b.buildLayerHeader(layer))
+
+	if len(layer.Contents) > 0 {
+		_, _ = builder.WriteString(fmt.Sprintf(`<div class="layer-content %v">`, layer.Layout))
+		for _, c := range layer.Contents {
+			content, err := b.buildContent(c)
+			if err != nil {
+				return "", err
+			}
+
+			_, _ = builder.WriteString(content)
+		}
+		_, _ = builder.WriteString("</div>")
+	}
+
+	if len(layer.Layers) > 0 {
+		children, err := b.buildGridLayers(
+			fmt.Sprintf("#%v > .layer-grid", id),
+			layer.Grid,
+			layer.Layers,
+		)
+		if err != nil {
+			return "", err
+		}
+
+		_, _ = builder.WriteString(fmt.Sprintf(`<div class="layer-grid">%v</div>`, children))
+	}
+
+	return fmt.Sprintf(
+		`<div id="%v" class="layer"%v>%v</div>`,
+		id,
+		styleAttr(layer.Css),
+		builder.String(),
 	), nil
 }
 
+// buildLayerHeader creates the optional title bar of a layer,
+// the header is skipped when there is nothing to show.
+func (b *IndexBuilder) buildLayerHeader(layer configs.Layer) string {
+	if layer.Title == "" && layer.Icon == "" && layer.Updated == "" {
+		return ""
+	}
+
+	var builder strings.Builder
+	builder.WriteString(`<div class="layer-header">`)
+
+	if layer.Icon != "" {
+		// icons containing a path are images, anything else is shown as text
+		if strings.Contains(layer.Icon, "/") {
+			builder.WriteString(fmt.Sprintf(`<img class="layer-icon" src="%v" alt="" />`, layer.Icon))
+		} else {
+			builder.WriteString(fmt.Sprintf(`<span class="layer-icon">%v</span>`, layer.Icon))
+		}
+	}
+
+	title := layer.Title
+	if layer.Link != "" {
+		title = fmt.Sprintf(`<a href="%v">%v</a>`, layer.Link, title)
+	}
+	builder.WriteString(fmt.Sprintf(`<span class="layer-title">%v</span>`, title))
+
+	if layer.Updated != "" {
+		id := stringFromIndex(&b.contentIndex)
+		b.elements[id] = fmt.Sprintf(
+			"element.innerHTML = formatAge(ageOf(%v))",
+			updatedScript(layer.Updated),
+		)
+		builder.WriteString(fmt.Sprintf(`<small id="%v" class="age"></small>`, id))
+	}
+
+	builder.WriteString("</div>")
+	return builder.String()
+}
+
+// updatedScript reads the update time of a `feed.store` value,
+// or the most recent update of any value when only a feed is given.
+func updatedScript(value string) string {
+	if strings.Contains(value, ".") {
+		return pathScript("updated", value)
+	}
+	return fmt.Sprintf("latestOf(%v)", pathScript("updated", value))
+}
+
 func (b *IndexBuilder) buildContent(content configs.Content) (string, error) {
 	var builder ContentBuilder
 	switch strings.ToLower(content.Type) {
 	case "text":
 		builder = b.textContent
+	case "badge":
+		builder = b.badgeContent
 	case "constant":
 		builder = b.constantContent
 	default:
@@ -160,22 +533,153 @@ func (b *IndexBuilder) buildContent(content configs.Content) (string, error) {
 }
 
 func (b *IndexBuilder) textContent(content configs.Content) (string, error) {
+	return b.dynamicContent(content, "div", content.Styles)
+}
+
+func (b *IndexBuilder) badgeContent(content configs.Content) (string, error) {
+	return b.dynamicContent(content, "span", append([]string{"badge"}, content.Styles...))
+}
+
+func (b *IndexBuilder) dynamicContent(content configs.Content, tag string, styles []string) (string, error) {
 	id := stringFromIndex(&b.contentIndex)
 	// TODO: this does not allow any option of customizing the text for things
 	// like data type conversions, or even formatting really.
-	b.elements[id] = fmt.Sprintf("element.innerHTML = `%v`", content.Text)
+	update := fmt.Sprintf("element.innerHTML = `%v`", content.Text)
+
+	rules, err := buildRules(content)
+	if err != nil {
+		return "", err
+	}
+	if rules != "" {
+		update += ";\n" + rules
+	}
+	if age := buildAge(content); age != "" {
+		update += ";\n" + age
+	}
+	b.elements[id] = update
 
 	return fmt.Sprintf(
-		`<div id="%v" class="%v"></div>`,
+		`<%v id="%v" class="%v"%v></%v>`,
+		tag,
 		id,
-		strings.Join(content.Styles, " "),
+		strings.Join(styles, " "),
+		styleAttr(content.Css),
+		tag,
 	), nil
 }
 
+// valueScript converts a value reference like "feed.store" to a javascript
+// expression reading it from the data object.
+func valueScript(value string) string {
+	return pathScript("data", value)
+}
+
+func pathScript(root, value string) string {
+	expr := root
+	for _, part := range strings.Split(value, ".") {
+		expr += fmt.Sprintf("?.[%q]", part)
+	}
+	return expr
+}
+
+// conditionScript creates the javascript expression of a condition,
+// values that can not be parsed only match "!=" as in Condition.Matches.
+func conditionScript(cond configs.Condition) string {
+	missing := cond.Operator == "!="
+	switch operand := cond.Operand.(type) {
+	case float64:
+		return fmt.Sprintf(
+			"(numberOf(value) === null ? %v : numberOf(value) %v %v)",
+			missing,
+			cond.Operator,
+			operand,
+		)
+	case bool:
+		if cond.Operator != "==" && cond.Operator != "!=" {
+			return "false"
+		}
+		return fmt.Sprintf(
+			"(boolOf(value) === null ? %v : boolOf(value) %v %v)",
+			missing,
+			cond.Operator,
+			operand,
+		)
+	default:
+		return fmt.Sprintf("String(value) %v %q", cond.Operator, fmt.Sprint(operand))
+	}
+}
+
+// buildRules creates the script to swap rule styles whenever
+// the element is updated, the first matching rule wins.
+func buildRules(content configs.Content) (string, error) {
+	if len(content.Rules) == 0 {
+		return "", nil
+	}
+
+	if content.Value == "" {
+		return "", fmt.Errorf("content rules require a value to compare against")
+	}
+
+	var (
+		builder strings.Builder
+		styles  []string
+	)
+
+	for _, rule := range content.Rules {
+		if rule.Style == "" {
+			return "", fmt.Errorf("content rule '%v' is missing a style", rule.When)
+		}
+		styles = append(styles, fmt.Sprintf("%q", rule.Style))
+	}
+
+	builder.WriteString(fmt.Sprintf("const value = %v;\n", valueScript(content.Value)))
+	builder.WriteString(fmt.Sprintf("element.classList.remove(%v);\n", strings.Join(styles, ", ")))
+	for i, rule := range content.Rules {
+		if i > 0 {
+			builder.WriteString(" else ")
+		}
+		builder.WriteString(fmt.Sprintf(
+			"if (%v) { element.classList.add(%q); }",
+			conditionScript(rule.When),
+			rule.Style,
+		))
+	}
+
+	return builder.String(), nil
+}
+
+// buildAge creates the script to show the age of the content value
+// and mark it as stale, ages are recalculated every update and on a timer.
+func buildAge(content configs.Content) string {
+	if !content.ShowAge && content.StaleAfter == "" {
+		return ""
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf(
+		"const age = ageOf(%v);\n",
+		pathScript("updated", content.Value),
+	))
+
+	if content.ShowAge {
+		builder.WriteString("element.insertAdjacentHTML('beforeend', `<small class=\"age\">${formatAge(age)}</small>`);\n")
+	}
+
+	if content.StaleAfter != "" {
+		builder.WriteString(fmt.Sprintf(
+			"element.classList.toggle(\"stale\", age === null || age > %v);",
+			content.StaleAfterDuration.Milliseconds(),
+		))
+	}
+
+	return builder.String()
+}
+
 func (b *IndexBuilder) constantContent(content configs.Content) (string, error) {
 	return fmt.Sprintf(
-		`<div class="%v">%v</div>`,
+		`<div class="%v"%v>%v</div>`,
 		strings.Join(content.Styles, " "),
+		styleAttr(content.Css),
 		content.Text,
 	), nil
 }
@@ -183,25 +687,24 @@ func (b *IndexBuilder) constantContent(content configs.Content) (string, error)
 func (b *IndexBuilder) buildStyles() (string, error) {
 	var builder strings.Builder
 
-	allStyles := make(map[string]string, 0)
-	for k := range defaultStyles {
-		allStyles[k] = defaultStyles[k]
-	}
-	for k := range b.dashboard.CustomStyles {
-		allStyles[k] = defaultStyles[k]
-	}
+	_, _ = builder.WriteString(b.buildTheme())
+	writeStyleRules(&builder, defaultStyles)
+	_, _ = builder.WriteString(b.buildGrid())
 
-	styleFormat := `%v {%v}`
-	for name, content := range allStyles {
-		_, _ = builder.WriteString(fmt.Sprintf(styleFormat, name, content))
+	// custom styles are written after the defaults so they are merged
+	// with and override any default rule of the same selector
+	writeStyleRules(&builder, b.dashboard.CustomStyles)
+	for _, css := range b.styleFiles {
+		_, _ = builder.WriteString(css)
 	}
+
 	return builder.String(), nil
 }
 
 func (b *IndexBuilder) buildScripts() (string, error) {
 	var builder strings.Builder
 
-	updateFormat := `function update%v(element, data) {
+	updateFormat := `function update%v(element, data, updated) {
 		%v;
 	}
 	`
@@ -212,10 +715,18 @@ func (b *IndexBuilder) buildScripts() (string, error) {
 		_, _ = builder.WriteString(fmt.Sprintf(updateFormat, n, m))
 	}
 
-	builder.WriteString("function updateall(data) {\n")
+	builder.WriteString(ageScripts)
+	builder.WriteString(conditionScripts)
+	if b.theme.toggle {
+		builder.WriteString(themeScripts)
+	}
+	builder.WriteString("function updateall(payload) {\n")
+	builder.WriteString("latest = payload;\n")
+	builder.WriteString("const data = payload.values;\n")
+	builder.WriteString("const updated = payload.updated;\n")
 	for id := range b.elements {
 		builder.WriteString(fmt.Sprintf(
-			`update%v(elements["%v"], data);
+			`update%v(elements["%v"], data, updated);
 			`,
 			id,
 			id,
@@ -225,9 +736,10 @@ func (b *IndexBuilder) buildScripts() (string, error) {
 
 	builder.WriteString(`
 	const elements = {};
+	let latest = null;
 	window.addEventListener('DOMContentLoaded', async () => {
-		const response = await fetch('/api/values');
-		const values = await response.json();
+		const response = await fetch('/api/values?updated=1');
+		const payload = await response.json();
 		`,
 	)
 
@@ -235,12 +747,48 @@ func (b *IndexBuilder) buildScripts() (string, error) {
 		_, _ = builder.WriteString(fmt.Sprintf(saveElementFormat, id, id))
 	}
 
-	builder.WriteString("updateall(values);")
+	builder.WriteString("updateall(payload);")
+	builder.WriteString(`
+		const events = new EventSource('/api/events');
+		events.addEventListener('values', (e) => updateall(JSON.parse(e.data)));
+		setInterval(() => latest && updateall(latest), 30000);`,
+	)
 	builder.WriteString("\n});")
+	builder.WriteString(b.buildKiosk())
 
 	return builder.String(), nil
 }
 
+// buildKiosk rotates to the next kiosk page on a timer
+// when the page is opened with `?kiosk`.
+func (b *IndexBuilder) buildKiosk() string {
+	if b.kiosk == nil {
+		return ""
+	}
+
+	pages := b.kiosk.Pages
+	if len(pages) == 0 {
+		for _, p := range b.pages {
+			pages = append(pages, p.path)
+		}
+	}
+
+	// a list of strings can always be marshalled
+	pagesJson, _ := json.Marshal(pages)
+	return fmt.Sprintf(`
+	if (new URLSearchParams(window.location.search).has('kiosk')) {
+		document.body.classList.add('kiosk');
+		const kioskPages = %v;
+		setTimeout(() => {
+			const next = (kioskPages.indexOf(window.location.pathname) + 1) %% kioskPages.length;
+			window.location.href = kioskPages[next] + '?kiosk';
+		}, %v);
+	}`,
+		string(pagesJson),
+		b.kiosk.EveryDuration.Milliseconds(),
+	)
+}
+
 func (b *IndexBuilder) Write(writer io.Writer) error {
 	tmpl, err := textTemplate.New("HTML").Parse(html)
 	if err != nil {
@@ -249,6 +797,7 @@ func (b *IndexBuilder) Write(writer io.Writer) error {
 
 	var (
 		meta    string
+		nav     string
 		body    string
 		styles  string
 		scripts string
@@ -258,6 +807,10 @@ func (b *IndexBuilder) Write(writer io.Writer) error {
 		return err
 	}
 
+	if nav, err = b.buildNav(); err != nil {
+		return err
+	}
+
 	if body, err = b.buildLayers(); err != nil {
 		return err
 	}
@@ -273,27 +826,68 @@ func (b *IndexBuilder) Write(writer io.Writer) error {
 	return tmpl.Execute(writer, map[string]interface{}{
 		"title":   b.dashboard.Title,
 		"meta":    meta,
+		"nav":     nav,
+		"toggle":  b.buildThemeToggle(),
 		"styles":  styles,
 		"body":    body,
 		"scripts": scripts,
 	})
 }
 
+// GenerateIndex builds the html of every dashboard page by path
 func (s *Server) GenerateIndex() error {
-	builder := &IndexBuilder{
-		dashboard: s.Config.Dashboard,
-		elements:  make(map[string]string),
+	dashboards := make(map[string]configs.Dashboard, len(s.Config.Dashboards)+1)
+	if s.Config.HasRootDashboard() {
+		dashboards["/"] = s.Config.Dashboard
+	}
+	for name, d := range s.Config.Dashboards {
+		if d.Title == "" {
+			d.Title = name
+		}
+		dashboards[configs.DashboardPath(name)] = d
 	}
 
-	var bWriter bytes.Buffer
-	err := builder.Write(&bWriter)
-	if err != nil {
-		return err
+	var pages []dashboardPage
+	for _, path := range s.Config.PagePaths() {
+		title := dashboards[path].Title
+		if title == "" {
+			title = "Home"
+		}
+		pages = append(pages, dashboardPage{path: path, title: title})
 	}
 
-	fmt.Println("str", bWriter.String())
+	indexFiles := make(map[string][]byte, len(pages))
+	for _, p := range pages {
+		theme, err := s.resolveTheme(dashboards[p.path].Theme)
+		if err != nil {
+			return err
+		}
+
+		styleFiles, err := s.loadStyleFiles(dashboards[p.path].StyleFiles)
+		if err != nil {
+			return err
+		}
+
+		builder := &IndexBuilder{
+			dashboard:  dashboards[p.path],
+			feeds:      s.Config.Feeds,
+			path:       p.path,
+			pages:      pages,
+			kiosk:      s.Config.Kiosk,
+			theme:      theme,
+			styleFiles: styleFiles,
+			elements:   make(map[string]string),
+		}
+
+		var bWriter bytes.Buffer
+		if err := builder.Write(&bWriter); err != nil {
+			return err
+		}
+
+		indexFiles[p.path] = bWriter.Bytes()
+	}
 
-	s.indexFile = bWriter.Bytes()
+	s.indexFiles = indexFiles
 	return nil
 }
 
@@ -303,5 +897,21 @@ func (s *Server) IndexHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Write(s.indexFile)
+	// without a main dashboard the first named dashboard is the home page
+	if !s.Config.HasRootDashboard() {
+		http.Redirect(w, r, s.Config.PagePaths()[0], http.StatusFound)
+		return
+	}
+
+	w.Write(s.indexFiles["/"])
+}
+
+func (s *Server) DashboardHandler(w http.ResponseWriter, r *http.Request) {
+	indexFile, ok := s.indexFiles[r.URL.Path]
+	if !ok || r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Write(indexFile)
 }
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+func TestConditionScript(t *testing.T) {
+	for _, tc := range []struct {
+		expr     string
+		expected string
+	}{
+		{expr: "value < 10", expected: "(numberOf(value) === null ? false : numberOf(value) < 10)"},
+		{expr: "value != 2.5", expected: "(numberOf(value) === null ? true : numberOf(value) != 2.5)"},
+		{expr: "value == true", expected: "(boolOf(value) === null ? false : boolOf(value) == true)"},
+		{expr: "value != false", expected: "(boolOf(value) === null ? true : boolOf(value) != false)"},
+		{expr: "value > true", expected: "false"},
+		{expr: `value == "down"`, expected: `String(value) == "down"`},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			cond, err := configs.ParseCondition(tc.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := conditionScript(cond); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestBuildStylesCustomAfterDefaults(t *testing.T) {
+	b := &IndexBuilder{
+		dashboard: configs.Dashboard{
+			CustomStyles: map[string]string{
+				".layer": "background: red;",
+				"body":   "font-size: 2em;",
+			},
+		},
+		styleFiles: []string{"h1 {color: blue;}"},
+	}
+
+	styles, err := b.buildStyles()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defaultLayer := strings.Index(styles, ".layer {"+defaultStyles[".layer"]+"}")
+	customLayer := strings.Index(styles, ".layer {background: red;}")
+	customBody := strings.Index(styles, "body {font-size: 2em;}")
+	styleFile := strings.Index(styles, "h1 {color: blue;}")
+	if defaultLayer < 0 || customLayer < 0 || customBody < 0 || styleFile < 0 {
+		t.Fatalf("expected default, custom and file styles, got %v", styles)
+	}
+	if customLayer < defaultLayer {
+		t.Error("expected custom styles to be written after the defaults")
+	}
+	if customBody < customLayer {
+		t.Error("expected custom styles to be sorted by selector")
+	}
+	if styleFile < customLayer {
+		t.Error("expected style files to be written after custom styles")
+	}
+}
+
+func TestWriteStyleRulesSorted(t *testing.T) {
+	var builder strings.Builder
+	writeStyleRules(&builder, map[string]string{
+		"nav":    "a: 1;",
+		".layer": "b: 2;",
+		"body":   "c: 3;",
+	})
+
+	expected := ".layer {b: 2;}body {c: 3;}nav {a: 1;}"
+	if got := builder.String(); got != expected {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestBuildGrid(t *testing.T) {
+	b := &IndexBuilder{
+		dashboard: configs.Dashboard{
+			Grid: configs.Grid{
+				Columns:     4,
+				RowHeight:   "10rem",
+				Breakpoints: []configs.Breakpoint{{MaxWidth: "600px", Columns: 1}},
+			},
+			Layers: []configs.Layer{
+				{X: 0, Y: 0, Width: 3, Height: 1},
+				{
+					X: 3, Y: 0, Width: 1, Height: 2,
+					Grid:   configs.Grid{Columns: 2, Gap: "1rem"},
+					Layers: []configs.Layer{{X: 1, Y: 0, Width: 1, Height: 1}},
+				},
+			},
+		},
+		elements: make(map[string]string),
+	}
+
+	body, err := b.buildLayers()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(body, `<div id="ab" class="layer"><div class="layer-grid"><div id="ac" class="layer"></div></div></div>`) {
+		t.Errorf("expected the child layer inside its parent grid, got %v", body)
+	}
+
+	grid := b.buildGrid()
+	for _, expected := range []string{
+		"main {display: grid;grid-template-columns: repeat(4, 1fr);grid-gap: .25rem;grid-auto-rows: 10rem;}",
+		"#aa {grid-column: 1 / span 3;grid-row: 1 / span 1;}",
+		"#ab {grid-column: 4 / span 1;grid-row: 1 / span 2;}",
+		"#ab > .layer-grid {display: grid;grid-template-columns: repeat(2, 1fr);grid-gap: 1rem;}",
+		"#ac {grid-column: 2 / span 1;grid-row: 1 / span 1;}",
+		"@media (max-width: 600px) {main {grid-template-columns: repeat(1, 1fr);}" +
+			"#aa {grid-column: auto / span 1;grid-row: auto / span 1;}" +
+			"#ab {grid-column: auto / span 1;grid-row: auto / span 2;}}",
+	} {
+		if !strings.Contains(grid, expected) {
+			t.Errorf("expected %v in %v", expected, grid)
+		}
+	}
+
+	if strings.Index(grid, "@media") < strings.Index(grid, "#ac {") {
+		t.Error("expected breakpoints after every placement")
+	}
+}
+
+func TestGenerateIndexWithoutRootDashboard(t *testing.T) {
+	styleFile := filepath.Join(t.TempDir(), "custom.css")
+	if err := os.WriteFile(styleFile, []byte(".custom-file {color: red;}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{
+		Config: &configs.Config{
+			Dashboards: map[string]configs.Dashboard{
+				"ops": {
+					Title:        "Operations",
+					CustomStyles: map[string]string{".custom-rule": "color: blue;"},
+					StyleFiles:   []string{styleFile},
+					Layers:       []configs.Layer{{Width: 1, Height: 1}},
+				},
+				"dev": {Layers: []configs.Layer{{Width: 1, Height: 1}}},
+			},
+		},
+		Store: newMemoryStore(),
+	}
+
+	if err := s.GenerateIndex(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, exists := s.indexFiles["/"]; exists {
+		t.Error("expected no root page without root layers")
+	}
+	if len(s.indexFiles) != 2 {
+		t.Fatalf("expected 2 pages, got %v", len(s.indexFiles))
+	}
+
+	ops := string(s.indexFiles["/d/ops"])
+	for _, expected := range []string{
+		"<title>Operations</title>",
+		".custom-rule {color: blue;}",
+		".custom-file {color: red;}",
+		`<a href="/d/dev" class="">dev</a><a href="/d/ops" class="active">Operations</a>`,
+	} {
+		if !strings.Contains(ops, expected) {
+			t.Errorf("expected %v in the ops page", expected)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	s.IndexHandler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "/d/dev" {
+		t.Errorf("expected a redirect to /d/dev, got %v %v", rec.Code, rec.Header().Get("Location"))
+	}
+}
+
+func TestGenerateIndexMissingStyleFile(t *testing.T) {
+	s := &Server{
+		Config: &configs.Config{
+			Dashboard: configs.Dashboard{
+				StyleFiles: []string{filepath.Join(t.TempDir(), "missing.css")},
+				Layers:     []configs.Layer{{Width: 1, Height: 1}},
+			},
+		},
+		Store: newMemoryStore(),
+	}
+
+	if err := s.GenerateIndex(); err == nil {
+		t.Error("expected an error for a missing style file")
+	}
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+const defaultMaxPages = 10
+
+// fetchPages continues requesting pages after the first until there are
+// no more pages or the max pages is reached, all page items are merged
+// into a single json array.
+func (s *Server) fetchPages(
+	client *http.Client,
+	first *http.Request,
+	feed *configs.FeedConfig,
+	body []byte,
+	header http.Header,
+) ([]byte, error) {
+	pagination := feed.Query.Pagination
+
+	maxPages := pagination.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+
+	items := pageItems(pagination, body)
+	page := 1
+	for ; page < maxPages; page++ {
+		req, err := nextPageRequest(first, pagination, page, body, header)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create page request: %w", err)
+		}
+		if req == nil {
+			break
+		}
+
+		body, header, err = s.fetchPage(client, req, feed)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch page %v: %w", page+1, err)
+		}
+
+		pageItems := pageItems(pagination, body)
+		if len(pageItems) == 0 {
+			break
+		}
+		items = append(items, pageItems...)
+	}
+
+	if page == maxPages {
+		log.Printf("feed '%v' stopped at max pages of %v\n", feed.Name, maxPages)
+	}
+
+	raws := make([]string, len(items))
+	for i, item := range items {
+		raws[i] = item.Raw
+	}
+	return []byte("[" + strings.Join(raws, ",") + "]"), nil
+}
+
+// setFirstPage adds the page size and starting page or offset to the first request
+func setFirstPage(req *http.Request, pagination *configs.FeedPagination) {
+	query := req.URL.Query()
+
+	switch pagination.Type {
+	case "page":
+		start := pagination.Start
+		if start == 0 {
+			start = 1
+		}
+		query.Set(pagination.Param, strconv.Itoa(start))
+	case "offset":
+		query.Set(pagination.Param, strconv.Itoa(pagination.Start))
+	}
+
+	if pagination.SizeParam != "" && pagination.Size > 0 {
+		query.Set(pagination.SizeParam, strconv.Itoa(pagination.Size))
+	}
+
+	req.URL.RawQuery = query.Encode()
+}
+
+func pageItems(pagination *configs.FeedPagination, body []byte) []gjson.Result {
+	result := gjson.ParseBytes(body)
+	if pagination.Items != "" {
+		result = result.Get(pagination.Items)
+	}
+
+	if !result.Exists() {
+		return nil
+	}
+	if !result.IsArray() {
+		return []gjson.Result{result}
+	}
+	return result.Array()
+}
+
+// nextPageRequest returns the request for the page index, starting at zero,
+// or nil if there are no more pages.
+func nextPageRequest(
+	first *http.Request,
+	pagination *configs.FeedPagination,
+	page int,
+	body []byte,
+	header http.Header,
+) (*http.Request, error) {
+	req := first.Clone(first.Context())
+	// only the first page is conditional
+	req.Header.Del("If-None-Match")
+	req.Header.Del("If-Modified-Since")
+	if first.GetBody != nil {
+		reqBody, err := first.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = reqBody
+	}
+
+	query := req.URL.Query()
+
+	switch pagination.Type {
+	case "link":
+		next := nextLink(header.Get("Link"))
+		if next == "" {
+			return nil, nil
+		}
+
+		nextUrl, err := req.URL.Parse(next)
+		if err != nil {
+			return nil, err
+		}
+		req.URL = nextUrl
+		req.Host = nextUrl.Host
+		return req, nil
+	case "cursor":
+		cursor := gjson.GetBytes(body, pagination.Cursor)
+		if !cursor.Exists() || cursor.String() == "" {
+			return nil, nil
+		}
+		query.Set(pagination.Param, cursor.String())
+	case "page":
+		if pagination.Size > 0 && len(pageItems(pagination, body)) < pagination.Size {
+			return nil, nil
+		}
+
+		start := pagination.Start
+		if start == 0 {
+			start = 1
+		}
+		query.Set(pagination.Param, strconv.Itoa(start+page))
+	case "offset":
+		if len(pageItems(pagination, body)) < pagination.Size {
+			return nil, nil
+		}
+		query.Set(pagination.Param, strconv.Itoa(pagination.Start+page*pagination.Size))
+	}
+
+	if pagination.SizeParam != "" && pagination.Size > 0 {
+		query.Set(pagination.SizeParam, strconv.Itoa(pagination.Size))
+	}
+
+	req.URL.RawQuery = query.Encode()
+	return req, nil
+}
+
+// nextLink finds the rel="next" url from a Link header such as
+// `<https://api.github.com/repos?page=2>; rel="next", <...>; rel="last"`
+func nextLink(header string) string {
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
+		}
+
+		for _, param := range parts[1:] {
+			param = strings.ReplaceAll(strings.TrimSpace(param), " ", "")
+			if param == `rel="next"` || param == "rel=next" {
+				return strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+func TestNextLink(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "empty", header: "", expected: ""},
+		{
+			name:     "next and last",
+			header:   `<https://api.example.com/repos?page=2>; rel="next", <https://api.example.com/repos?page=5>; rel="last"`,
+			expected: "https://api.example.com/repos?page=2",
+		},
+		{
+			name:     "next after prev",
+			header:   `<https://api.example.com/repos?page=1>; rel="prev", <https://api.example.com/repos?page=3>; rel="next"`,
+			expected: "https://api.example.com/repos?page=3",
+		},
+		{name: "unquoted", header: `</repos?page=2>; rel=next`, expected: "/repos?page=2"},
+		{name: "spaced", header: `</repos?page=2>; rel = "next"`, expected: "/repos?page=2"},
+		{name: "extra params", header: `</repos?page=2>; title="more"; rel="next"`, expected: "/repos?page=2"},
+		{name: "last only", header: `</repos?page=5>; rel="last"`, expected: ""},
+		{name: "no params", header: `</repos?page=2>`, expected: ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := nextLink(tc.header); got != tc.expected {
+				t.Errorf("expected '%v', got '%v'", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNextPageRequest(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		pagination configs.FeedPagination
+		page       int
+		body       string
+		link       string
+		expected   string
+	}{
+		{
+			name:       "page",
+			pagination: configs.FeedPagination{Type: "page", Param: "page"},
+			page:       1,
+			body:       `[1,2]`,
+			expected:   "https://api.example.com/items?page=2",
+		},
+		{
+			name:       "page with start and size",
+			pagination: configs.FeedPagination{Type: "page", Param: "p", Start: 0, Size: 2, SizeParam: "size"},
+			page:       2,
+			body:       `[1,2]`,
+			expected:   "https://api.example.com/items?p=3&size=2",
+		},
+		{
+			name:       "page short of size",
+			pagination: configs.FeedPagination{Type: "page", Param: "page", Size: 3},
+			page:       1,
+			body:       `[1,2]`,
+		},
+		{
+			name:       "offset",
+			pagination: configs.FeedPagination{Type: "offset", Param: "offset", Start: 10, Size: 2, Items: "items"},
+			page:       2,
+			body:       `{"items":[1,2]}`,
+			expected:   "https://api.example.com/items?offset=14",
+		},
+		{
+			name:       "offset short of size",
+			pagination: configs.FeedPagination{Type: "offset", Param: "offset", Size: 2, Items: "items"},
+			page:       1,
+			body:       `{"items":[1]}`,
+		},
+		{
+			name:       "cursor",
+			pagination: configs.FeedPagination{Type: "cursor", Param: "after", Cursor: "meta.next"},
+			page:       1,
+			body:       `{"meta":{"next":"abc"}}`,
+			expected:   "https://api.example.com/items?after=abc",
+		},
+		{
+			name:       "cursor empty",
+			pagination: configs.FeedPagination{Type: "cursor", Param: "after", Cursor: "meta.next"},
+			page:       1,
+			body:       `{"meta":{"next":""}}`,
+		},
+		{
+			name:       "link",
+			pagination: configs.FeedPagination{Type: "link"},
+			page:       1,
+			link:       `</items?page=2>; rel="next"`,
+			expected:   "https://api.example.com/items?page=2",
+		},
+		{
+			name:       "link absolute",
+			pagination: configs.FeedPagination{Type: "link"},
+			page:       1,
+			link:       `<https://other.example.com/items?page=2>; rel="next"`,
+			expected:   "https://other.example.com/items?page=2",
+		},
+		{
+			name:       "link missing",
+			pagination: configs.FeedPagination{Type: "link"},
+			page:       1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			first, err := http.NewRequest(http.MethodGet, "https://api.example.com/items", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			first.Header.Set("If-None-Match", `"etag"`)
+
+			header := http.Header{}
+			if tc.link != "" {
+				header.Set("Link", tc.link)
+			}
+
+			req, err := nextPageRequest(first, &tc.pagination, tc.page, []byte(tc.body), header)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tc.expected == "" {
+				if req != nil {
+					t.Fatalf("expected no more pages, got %v", req.URL)
+				}
+				return
+			}
+			if req == nil {
+				t.Fatalf("expected %v, got no more pages", tc.expected)
+			}
+			if got := req.URL.String(); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+			if req.Header.Get("If-None-Match") != "" {
+				t.Error("expected only the first page to be conditional")
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/miniscruff/dashy/configs"
+	"github.com/miniscruff/dashy/metrics"
+)
+
+type promSeries struct {
+	Labels map[string]interface{} `json:"labels"`
+	Value  interface{}            `json:"value"`
+	Values []interface{}          `json:"values,omitempty"`
+	Times  []float64              `json:"times,omitempty"`
+}
+
+type promResult struct {
+	Value  interface{}   `json:"value"`
+	Values []interface{} `json:"values"`
+	Times  []float64     `json:"times,omitempty"`
+	Series []promSeries  `json:"series"`
+}
+
+func (s *Server) fetchPrometheus(feed *configs.FeedConfig) ([]byte, error) {
+	prom := feed.Prometheus
+	tmpl := s.newQueryTemplate(feed)
+
+	baseUrl, err := tmpl.Render("url", prom.Url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render url: %w", err)
+	}
+
+	query, err := tmpl.Render("query", prom.Query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render query: %w", err)
+	}
+
+	now := time.Now().UTC()
+	endpoint := "/api/v1/query"
+	params := url.Values{}
+	params.Set("query", query)
+
+	if prom.Range != nil {
+		endpoint = "/api/v1/query_range"
+		params.Set("start", strconv.FormatInt(now.Add(-prom.Range.SinceDuration).Unix(), 10))
+		params.Set("end", strconv.FormatInt(now.Unix(), 10))
+		params.Set("step", strconv.FormatFloat(prom.Range.StepDuration.Seconds(), 'f', -1, 64))
+	} else {
+		params.Set("time", strconv.FormatInt(now.Unix(), 10))
+	}
+
+	queryUrl := strings.TrimSuffix(baseUrl, "/") + endpoint + "?" + params.Encode()
+	req, err := http.NewRequest(http.MethodGet, queryUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	for k, v := range prom.Headers {
+		header, err := tmpl.Render(k, v)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render header '%v': %w", k, err)
+		}
+		req.Header.Add(k, s.Store.StringOrVar(header))
+	}
+
+	if err := s.limits.Take(req.URL.Hostname()); err != nil {
+		return nil, fmt.Errorf("unable to request '%v': %w", req.URL.Hostname(), err)
+	}
+
+	client := &http.Client{}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get response: %w", err)
+	}
+	defer res.Body.Close()
+	s.limits.Observe(req.URL.Hostname(), res)
+
+	metrics.FeedResponses.WithLabelValues(feed.Name, strconv.Itoa(res.StatusCode)).Inc()
+
+	bodyBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response bytes: %w", err)
+	}
+
+	if !gjson.ValidBytes(bodyBytes) {
+		return nil, fmt.Errorf("body is not a valid JSON, status code '%v'", res.StatusCode)
+	}
+
+	body := gjson.ParseBytes(bodyBytes)
+	if body.Get("status").String() != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %v", body.Get("error").String())
+	}
+
+	return convertPrometheus(body.Get("data"))
+}
+
+// convertPrometheus flattens a query result into a json document
+// of scalar and array values for store paths.
+func convertPrometheus(data gjson.Result) ([]byte, error) {
+	result := data.Get("result")
+	converted := promResult{
+		Values: []interface{}{},
+		Series: []promSeries{},
+	}
+
+	switch data.Get("resultType").String() {
+	case "scalar", "string":
+		converted.Value = promValue(result)
+		converted.Values = append(converted.Values, converted.Value)
+	case "vector":
+		for _, r := range result.Array() {
+			value := promValue(r.Get("value"))
+			converted.Values = append(converted.Values, value)
+			converted.Series = append(converted.Series, promSeries{
+				Labels: promLabels(r),
+				Value:  value,
+			})
+		}
+		if len(converted.Values) > 0 {
+			converted.Value = converted.Values[0]
+		}
+	case "matrix":
+		for _, r := range result.Array() {
+			series := promSeries{
+				Labels: promLabels(r),
+				Values: []interface{}{},
+				Times:  []float64{},
+			}
+			for _, sample := range r.Get("values").Array() {
+				series.Times = append(series.Times, sample.Get("0").Float())
+				series.Values = append(series.Values, promValue(sample))
+			}
+			if len(series.Values) > 0 {
+				series.Value = series.Values[len(series.Values)-1]
+			}
+			converted.Series = append(converted.Series, series)
+		}
+		if len(converted.Series) > 0 {
+			first := converted.Series[0]
+			converted.Value = first.Value
+			converted.Values = first.Values
+			converted.Times = first.Times
+		}
+	default:
+		return nil, errors.New("prometheus result type not supported")
+	}
+
+	return json.Marshal(converted)
+}
+
+func promLabels(result gjson.Result) map[string]interface{} {
+	labels, ok := result.Get("metric").Value().(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+	return labels
+}
+
+// promValue converts a [time, "value"] sample to a number when possible,
+// NaN and infinite values are not valid json so they become null.
+func promValue(sample gjson.Result) interface{} {
+	raw := sample.Get("1").String()
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return raw
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+	return f
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+func TestConvertPrometheus(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{
+			name:     "scalar",
+			data:     `{"resultType":"scalar","result":[1650000000,"42.5"]}`,
+			expected: `{"value":42.5,"values":[42.5],"series":[]}`,
+		},
+		{
+			name:     "string",
+			data:     `{"resultType":"string","result":[1650000000,"up"]}`,
+			expected: `{"value":"up","values":["up"],"series":[]}`,
+		},
+		{
+			name: "vector",
+			data: `{"resultType":"vector","result":[
+				{"metric":{"job":"a"},"value":[1650000000,"1"]},
+				{"metric":{"job":"b"},"value":[1650000000,"NaN"]}
+			]}`,
+			expected: `{"value":1,"values":[1,null],"series":[` +
+				`{"labels":{"job":"a"},"value":1},` +
+				`{"labels":{"job":"b"},"value":null}]}`,
+		},
+		{
+			name: "matrix",
+			data: `{"resultType":"matrix","result":[
+				{"metric":{"job":"a"},"values":[[1650000000,"1"],[1650000060,"2"]]}
+			]}`,
+			expected: `{"value":2,"values":[1,2],"times":[1650000000,1650000060],"series":[` +
+				`{"labels":{"job":"a"},"value":2,"values":[1,2],"times":[1650000000,1650000060]}]}`,
+		},
+		{
+			name:     "empty vector",
+			data:     `{"resultType":"vector","result":[]}`,
+			expected: `{"value":null,"values":[],"series":[]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			converted, err := convertPrometheus(gjson.Parse(tc.data))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(converted) != tc.expected {
+				t.Errorf("expected %v\ngot %v", tc.expected, string(converted))
+			}
+		})
+	}
+
+	if _, err := convertPrometheus(gjson.Parse(`{"resultType":"other"}`)); err == nil {
+		t.Error("expected an error for an unsupported result type")
+	}
+}
+
+func TestFetchPrometheus(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   configs.FeedPrometheus
+		path     string
+		params   []string
+		response string
+		expected string
+		fails    bool
+	}{
+		{
+			name:     "instant",
+			config:   configs.FeedPrometheus{Query: "up", Headers: map[string]string{"Authorization": "token"}},
+			path:     "/api/v1/query",
+			params:   []string{"query", "time"},
+			response: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"1"]}]}}`,
+			expected: "1",
+		},
+		{
+			name: "range",
+			config: configs.FeedPrometheus{
+				Query: "rate(x[5m])",
+				Range: &configs.FeedPrometheusRange{
+					Since:         "1h",
+					Step:          "1m",
+					SinceDuration: time.Hour,
+					StepDuration:  time.Minute,
+				},
+			},
+			path:     "/api/v1/query_range",
+			params:   []string{"query", "start", "end", "step"},
+			response: `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[[1,"3"],[2,"4"]]}]}}`,
+			expected: "4",
+		},
+		{
+			name:     "error",
+			config:   configs.FeedPrometheus{Query: "bad("},
+			path:     "/api/v1/query",
+			params:   []string{"query"},
+			response: `{"status":"error","error":"parse error"}`,
+			fails:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			prom := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tc.path {
+					t.Errorf("expected path '%v', got '%v'", tc.path, r.URL.Path)
+				}
+				for _, p := range tc.params {
+					if r.URL.Query().Get(p) == "" {
+						t.Errorf("missing param '%v'", p)
+					}
+				}
+				if tc.config.Range != nil && r.URL.Query().Get("step") != "60" {
+					t.Errorf("expected a step of 60 seconds, got '%v'", r.URL.Query().Get("step"))
+				}
+				if r.URL.Query().Get("query") != tc.config.Query {
+					t.Errorf("expected query '%v', got '%v'", tc.config.Query, r.URL.Query().Get("query"))
+				}
+				for k, v := range tc.config.Headers {
+					if r.Header.Get(k) != v {
+						t.Errorf("expected header '%v' to be '%v'", k, v)
+					}
+				}
+				w.Write([]byte(tc.response))
+			}))
+			defer prom.Close()
+
+			config := tc.config
+			config.Url = prom.URL + "/"
+			s := &Server{Store: newMemoryStore()}
+
+			body, err := s.fetchPrometheus(&configs.FeedConfig{Name: "prom", Type: "prometheus", Prometheus: &config})
+			if tc.fails {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if value := gjson.GetBytes(body, "value").Raw; value != tc.expected {
+				t.Errorf("expected value '%v', got '%v'", tc.expected, value)
+			}
+		})
+	}
+}
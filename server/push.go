@@ -0,0 +1,150 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/miniscruff/dashy/configs"
+	"github.com/miniscruff/dashy/metrics"
+)
+
+const (
+	defaultSignatureHeader = "X-Signature-256"
+	maxPushBytes           = 1 << 20
+)
+
+func (s *Server) PushFeedHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	feedName := r.URL.Path[10:]
+
+	feed := s.Config.FeedByName(feedName)
+	if feed == nil || !feed.IsPush() {
+		log.Printf("push feed not found: '%v'\n", feedName)
+		http.NotFound(w, r)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPushBytes))
+	if err != nil {
+		http.Error(w, "unable to read body", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.verifyPush(feed, r.Header, body); err != nil {
+		log.Println(fmt.Errorf("push to '%v' rejected: %w", feed.Name, err))
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	if err := s.PushFeed(feed, body); err != nil {
+		status := http.StatusBadRequest
+		var storeErr *storeError
+		if errors.As(err, &storeErr) {
+			status = http.StatusInternalServerError
+		}
+		http.Error(w, fmt.Errorf("unable to push feed: %w", err).Error(), status)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// storeError is a failure to save pushed values rather than a bad body
+type storeError struct {
+	err error
+}
+
+func (e *storeError) Error() string {
+	return fmt.Sprintf("unable to store data: %v", e.err)
+}
+
+func (e *storeError) Unwrap() error {
+	return e.err
+}
+
+// verifyPush checks the hex encoded HMAC SHA256 signature of the body,
+// an optional "sha256=" prefix is allowed as sent by GitHub.
+// Feeds without a secret must opt out with insecure.
+func (s *Server) verifyPush(feed *configs.FeedConfig, header http.Header, body []byte) error {
+	if feed.Push.Secret == "" && feed.Push.Insecure {
+		return nil
+	}
+
+	secret := s.Store.StringOrVar(feed.Push.Secret)
+	if secret == "" {
+		return errors.New("push secret is empty")
+	}
+
+	headerName := feed.Push.Header
+	if headerName == "" {
+		headerName = defaultSignatureHeader
+	}
+
+	signature := strings.TrimPrefix(header.Get(headerName), "sha256=")
+	if signature == "" {
+		return fmt.Errorf("missing signature header '%v'", headerName)
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return errors.New("signature is not hex encoded")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return errors.New("signature does not match")
+	}
+
+	return nil
+}
+
+// PushFeed stores values from a pushed body as if the feed was fetched.
+func (s *Server) PushFeed(feed *configs.FeedConfig, body []byte) error {
+	log.Printf("pushing feed: %v\n", feed.Name)
+
+	if !gjson.ValidBytes(body) {
+		metrics.FeedFetches.WithLabelValues(feed.Name, "failure").Inc()
+		return errors.New("body is not a valid JSON")
+	}
+
+	if err := s.assert(feed, body); err != nil {
+		metrics.FeedFetches.WithLabelValues(feed.Name, "failure").Inc()
+		s.feedFailed(feed, err)
+		return err
+	}
+
+	results := extractValues(feed.Store, body)
+	if err := transformValues(feed.Store, results); err != nil {
+		metrics.FeedFetches.WithLabelValues(feed.Name, "failure").Inc()
+		s.feedFailed(feed, err)
+		return err
+	}
+	metrics.FeedFetches.WithLabelValues(feed.Name, "success").Inc()
+
+	err := s.Store.SetValues(feed, results)
+	if err != nil {
+		return &storeError{err: err}
+	}
+
+	metrics.FeedLastSuccess.WithLabelValues(feed.Name).SetToCurrentTime()
+	s.feedSucceeded(feed)
+	s.CheckAlerts(feed)
+	s.PublishValues()
+
+	log.Printf("feed pushed: %v\n", feed.Name)
+	return nil
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+// failingStore fails to save values to test store errors
+type failingStore struct {
+	*memoryStore
+}
+
+func (f failingStore) SetValues(*configs.FeedConfig, map[string]gjson.Result) error {
+	return errors.New("store is down")
+}
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestPushFeedHandler(t *testing.T) {
+	const body = `{"version":"1.2.0"}`
+	t.Setenv("DASHY_PUSH_SECRET", "hunter2")
+
+	for _, tc := range []struct {
+		name      string
+		push      configs.FeedPush
+		header    string
+		signature string
+		body      string
+		failStore bool
+		status    int
+	}{
+		{
+			name:      "valid signature",
+			push:      configs.FeedPush{Secret: "env:DASHY_PUSH_SECRET"},
+			signature: sign("hunter2", body),
+			status:    http.StatusOK,
+		},
+		{
+			name:      "sha256 prefix",
+			push:      configs.FeedPush{Secret: "hunter2"},
+			signature: "sha256=" + sign("hunter2", body),
+			status:    http.StatusOK,
+		},
+		{
+			name:      "custom header",
+			push:      configs.FeedPush{Secret: "hunter2", Header: "X-Hub-Signature-256"},
+			header:    "X-Hub-Signature-256",
+			signature: sign("hunter2", body),
+			status:    http.StatusOK,
+		},
+		{
+			name:      "wrong signature",
+			push:      configs.FeedPush{Secret: "hunter2"},
+			signature: sign("wrong", body),
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:      "tampered body",
+			push:      configs.FeedPush{Secret: "hunter2"},
+			signature: sign("hunter2", body),
+			body:      `{"version":"6.6.6"}`,
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:   "missing header",
+			push:   configs.FeedPush{Secret: "hunter2"},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:      "not hex",
+			push:      configs.FeedPush{Secret: "hunter2"},
+			signature: "not-a-signature",
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:      "empty resolved secret",
+			push:      configs.FeedPush{Secret: "env:DASHY_MISSING_SECRET"},
+			signature: sign("", body),
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:   "insecure",
+			push:   configs.FeedPush{Insecure: true},
+			status: http.StatusOK,
+		},
+		{
+			name:   "insecure with secret",
+			push:   configs.FeedPush{Secret: "hunter2", Insecure: true},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "invalid json",
+			push:   configs.FeedPush{Insecure: true},
+			body:   `{"version":`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:      "store failure",
+			push:      configs.FeedPush{Insecure: true},
+			failStore: true,
+			status:    http.StatusInternalServerError,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			feed := configs.FeedConfig{
+				Name:  "deploys",
+				Type:  "push",
+				Push:  &tc.push,
+				Store: []configs.FeedStore{{Name: "version", Path: "version"}},
+			}
+			memory := newMemoryStore()
+			s := &Server{
+				Config: &configs.Config{Feeds: []configs.FeedConfig{feed}},
+				Store:  memory,
+			}
+			if tc.failStore {
+				s.Store = failingStore{memory}
+			}
+
+			reqBody := tc.body
+			if reqBody == "" {
+				reqBody = body
+			}
+			req := httptest.NewRequest(http.MethodPost, "/api/push/deploys", strings.NewReader(reqBody))
+			if tc.signature != "" {
+				header := tc.header
+				if header == "" {
+					header = defaultSignatureHeader
+				}
+				req.Header.Set(header, tc.signature)
+			}
+
+			rec := httptest.NewRecorder()
+			s.PushFeedHandler(rec, req)
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %v, got %v: %v", tc.status, rec.Code, rec.Body.String())
+			}
+
+			values, _ := memory.GetValues()
+			stored := values["deploys"]["version"]
+			if tc.status == http.StatusOK && stored != "1.2.0" {
+				t.Errorf("expected version 1.2.0 to be stored, got %v", stored)
+			}
+			if tc.status != http.StatusOK && stored != nil {
+				t.Errorf("expected nothing to be stored, got %v", stored)
+			}
+		})
+	}
+}
+
+func TestPushFeedHandlerUnknownFeed(t *testing.T) {
+	s := &Server{
+		Config: &configs.Config{Feeds: []configs.FeedConfig{{Name: "weather"}}},
+		Store:  newMemoryStore(),
+	}
+
+	rec := httptest.NewRecorder()
+	s.PushFeedHandler(rec, httptest.NewRequest(http.MethodPost, "/api/push/weather", strings.NewReader("{}")))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status %v for a feed that is not push, got %v", http.StatusNotFound, rec.Code)
+	}
+}
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+var errRateLimited = errors.New("rate limit exceeded")
+
+// hostLimiter tracks configured token buckets per host as well as
+// hosts blocked by rate limit headers, the zero value allows everything.
+type hostLimiter struct {
+	mu      sync.Mutex
+	configs []configs.RateLimitConfig
+	hosts   map[string]*hostLimit
+}
+
+type hostLimit struct {
+	rate         float64
+	capacity     float64
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+func (l *hostLimiter) configure(limits []configs.RateLimitConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.configs = limits
+	l.hosts = nil
+}
+
+// limit returns the limit for a host, the lock must be held
+func (l *hostLimiter) limit(host string, now time.Time) *hostLimit {
+	if l.hosts == nil {
+		l.hosts = make(map[string]*hostLimit)
+	}
+
+	if limit, exists := l.hosts[host]; exists {
+		limit.refill(now)
+		return limit
+	}
+
+	limit := &hostLimit{last: now}
+	for _, c := range l.configs {
+		if !strings.EqualFold(c.Host, host) {
+			continue
+		}
+
+		burst := c.Burst
+		if burst <= 0 {
+			burst = c.Requests
+		}
+
+		limit.rate = float64(c.Requests) / c.PerDuration.Seconds()
+		limit.capacity = float64(burst)
+		limit.tokens = limit.capacity
+	}
+
+	l.hosts[host] = limit
+	return limit
+}
+
+func (h *hostLimit) refill(now time.Time) {
+	if h.rate > 0 {
+		h.tokens += now.Sub(h.last).Seconds() * h.rate
+		if h.tokens > h.capacity {
+			h.tokens = h.capacity
+		}
+	}
+	h.last = now
+}
+
+func (h *hostLimit) available(now time.Time) bool {
+	if now.Before(h.blockedUntil) {
+		return false
+	}
+	return h.rate == 0 || h.tokens >= 1
+}
+
+// Available reports if a request to the host could be made now
+func (l *hostLimiter) Available(host string) bool {
+	if host == "" {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	return l.limit(host, now).available(now)
+}
+
+// Take uses one request of the host budget, returning errRateLimited
+// if there is none left.
+func (l *hostLimiter) Take(host string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	limit := l.limit(host, now)
+	if !limit.available(now) {
+		return errRateLimited
+	}
+
+	if limit.rate > 0 {
+		limit.tokens--
+	}
+	return nil
+}
+
+// Observe blocks the host until the reset time when a response reports
+// no remaining requests through `X-RateLimit-*` or `Retry-After` headers.
+func (l *hostLimiter) Observe(host string, res *http.Response) {
+	now := time.Now()
+	var until time.Time
+
+	remaining := res.Header.Get("X-RateLimit-Remaining")
+	if remaining == "0" {
+		until = parseReset(res.Header.Get("X-RateLimit-Reset"), now)
+	}
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		if retry := parseReset(res.Header.Get("Retry-After"), now); retry.After(until) {
+			until = retry
+		}
+	}
+
+	if until.IsZero() {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limit := l.limit(host, now)
+	if until.After(limit.blockedUntil) {
+		limit.blockedUntil = until
+	}
+}
+
+// parseReset reads a reset header as either unix seconds or seconds from now,
+// an unknown value blocks for a minute.
+func parseReset(value string, now time.Time) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		if t, err := http.ParseTime(value); err == nil {
+			return t
+		}
+		return now.Add(time.Minute)
+	}
+
+	// anything before 2001 is a delta rather than a unix timestamp
+	if seconds < 1e9 {
+		return now.Add(time.Duration(seconds) * time.Second)
+	}
+	return time.Unix(seconds, 0)
+}
+
+// feedHost returns the upstream host of http based feeds
+func feedHost(feed *configs.FeedConfig) string {
+	var rawUrl string
+	switch strings.ToLower(feed.Type) {
+	case "", "http":
+		rawUrl = feed.Query.Url
+	case "prometheus":
+		rawUrl = feed.Prometheus.Url
+	default:
+		return ""
+	}
+
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
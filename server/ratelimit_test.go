@@ -0,0 +1,112 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+func TestHostLimitRefill(t *testing.T) {
+	var limiter hostLimiter
+	limiter.configure([]configs.RateLimitConfig{
+		{Host: "api.example.com", Requests: 2, Per: "1s", PerDuration: time.Second, Burst: 3},
+	})
+
+	now := time.Now()
+	limiter.mu.Lock()
+	limit := limiter.limit("API.example.com", now)
+	limiter.mu.Unlock()
+
+	if limit.tokens != 3 {
+		t.Fatalf("expected a full burst of 3 tokens, got %v", limit.tokens)
+	}
+
+	limit.tokens = 0
+	if limit.available(now) {
+		t.Error("expected no requests available without tokens")
+	}
+
+	limit.refill(now.Add(250 * time.Millisecond))
+	if limit.tokens != 0.5 || limit.available(now) {
+		t.Errorf("expected half a token to be unavailable, got %v", limit.tokens)
+	}
+
+	limit.refill(now.Add(time.Second))
+	if limit.tokens != 2 || !limit.available(now) {
+		t.Errorf("expected 2 tokens after a second, got %v", limit.tokens)
+	}
+
+	limit.refill(now.Add(time.Minute))
+	if limit.tokens != 3 {
+		t.Errorf("expected tokens to be capped at the burst of 3, got %v", limit.tokens)
+	}
+}
+
+func TestHostLimiterTake(t *testing.T) {
+	var limiter hostLimiter
+	limiter.configure([]configs.RateLimitConfig{
+		{Host: "api.example.com", Requests: 2, Per: "1h", PerDuration: time.Hour},
+	})
+
+	for i := 0; i < 2; i++ {
+		if err := limiter.Take("api.example.com"); err != nil {
+			t.Fatalf("expected request %v to be allowed, got %v", i+1, err)
+		}
+	}
+	if err := limiter.Take("api.example.com"); err != errRateLimited {
+		t.Errorf("expected %v, got %v", errRateLimited, err)
+	}
+	if limiter.Available("api.example.com") {
+		t.Error("expected the host to be unavailable")
+	}
+
+	for i := 0; i < 5; i++ {
+		if err := limiter.Take("other.example.com"); err != nil {
+			t.Fatalf("expected unlimited hosts to be allowed, got %v", err)
+		}
+	}
+}
+
+func TestHostLimiterObserve(t *testing.T) {
+	var limiter hostLimiter
+	res := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": []string{"120"}},
+	}
+
+	limiter.Observe("api.example.com", res)
+	if limiter.Available("api.example.com") {
+		t.Error("expected the host to be blocked after a 429")
+	}
+	if !limiter.Available("other.example.com") {
+		t.Error("expected other hosts to be available")
+	}
+}
+
+func TestParseReset(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name     string
+		value    string
+		expected time.Time
+	}{
+		{name: "empty", value: "", expected: time.Time{}},
+		{name: "delta", value: "30", expected: now.Add(30 * time.Second)},
+		{name: "zero delta", value: "0", expected: now},
+		{name: "unix", value: "1714568400", expected: time.Unix(1714568400, 0)},
+		{
+			name:     "http date",
+			value:    "Wed, 01 May 2024 12:05:00 GMT",
+			expected: time.Date(2024, 5, 1, 12, 5, 0, 0, time.UTC),
+		},
+		{name: "unknown", value: "soon", expected: now.Add(time.Minute)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := parseReset(tc.value, now); !got.Equal(tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/miniscruff/dashy/configs"
+	"github.com/miniscruff/dashy/store"
+)
+
+// redisPool shares clients between redis feeds by url,
+// an empty url uses the same redis as the store.
+type redisPool struct {
+	mu      sync.Mutex
+	clients map[string]*redis.Client
+}
+
+func (p *redisPool) client(env configs.EnvConfig, url string) (*redis.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if client, exists := p.clients[url]; exists {
+		return client, nil
+	}
+
+	var (
+		opts *redis.Options
+		err  error
+	)
+
+	if url == "" {
+		opts, err = store.RedisOptions(env)
+	} else {
+		opts, err = redis.ParseURL(url)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if p.clients == nil {
+		p.clients = make(map[string]*redis.Client)
+	}
+	client := redis.NewClient(opts)
+	p.clients[url] = client
+	return client, nil
+}
+
+func (s *Server) fetchRedis(feed *configs.FeedConfig) ([]byte, error) {
+	tmpl := s.newQueryTemplate(feed)
+
+	redisUrl, err := tmpl.Render("url", feed.Redis.Url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render url: %w", err)
+	}
+
+	client, err := s.redises.client(s.Config.Env, s.Store.StringOrVar(redisUrl))
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to redis: %w", err)
+	}
+
+	ctx := context.Background()
+	doc := make(map[string]interface{}, len(feed.Redis.Keys))
+	for _, k := range feed.Redis.Keys {
+		key, err := tmpl.Render(k.Name, k.Key)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render key '%v': %w", k.Name, err)
+		}
+
+		value, err := readRedisKey(ctx, client, key, k)
+		if err != nil && err != redis.Nil {
+			return nil, fmt.Errorf("unable to read key '%v': %w", key, err)
+		}
+		doc[k.Name] = value
+	}
+
+	return json.Marshal(doc)
+}
+
+func readRedisKey(ctx context.Context, client *redis.Client, key string, k configs.FeedRedisKey) (interface{}, error) {
+	stop := int64(-1)
+	if k.Stop != nil {
+		stop = *k.Stop
+	}
+
+	switch k.Type {
+	case "hash":
+		hash, err := client.HGetAll(ctx, key).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		values := make(map[string]interface{}, len(hash))
+		for field, v := range hash {
+			values[field] = redisValue(v)
+		}
+		return values, nil
+	case "list":
+		list, err := client.LRange(ctx, key, k.Start, stop).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		values := make([]interface{}, len(list))
+		for i, v := range list {
+			values[i] = redisValue(v)
+		}
+		return values, nil
+	case "zrange":
+		var members []redis.Z
+		var err error
+		if k.Reverse {
+			members, err = client.ZRevRangeWithScores(ctx, key, k.Start, stop).Result()
+		} else {
+			members, err = client.ZRangeWithScores(ctx, key, k.Start, stop).Result()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		values := make([]map[string]interface{}, len(members))
+		for i, m := range members {
+			var score interface{} = m.Score
+			// scores can be +inf or -inf which json can not represent
+			if math.IsInf(m.Score, 0) {
+				score = nil
+			}
+			values[i] = map[string]interface{}{
+				"member": m.Member,
+				"score":  score,
+			}
+		}
+		return values, nil
+	case "llen":
+		return client.LLen(ctx, key).Result()
+	case "scard":
+		return client.SCard(ctx, key).Result()
+	case "zcard":
+		return client.ZCard(ctx, key).Result()
+	case "xlen":
+		return client.XLen(ctx, key).Result()
+	}
+
+	value, err := client.Get(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	return redisValue(value), nil
+}
+
+// redisValue converts numeric strings, such as counters, to numbers,
+// NaN and Inf are kept as strings as they can not be written to json.
+func redisValue(value string) interface{} {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return value
+	}
+	return f
+}
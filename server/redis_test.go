@@ -0,0 +1,31 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRedisValue(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		expected interface{}
+	}{
+		{value: "42", expected: 42.0},
+		{value: "-1.5", expected: -1.5},
+		{value: "up", expected: "up"},
+		{value: "", expected: ""},
+		{value: "NaN", expected: "NaN"},
+		{value: "Inf", expected: "Inf"},
+		{value: "-inf", expected: "-inf"},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			got := redisValue(tc.value)
+			if got != tc.expected {
+				t.Errorf("expected %#v, got %#v", tc.expected, got)
+			}
+			if _, err := json.Marshal(got); err != nil {
+				t.Errorf("expected a json value, got %v", err)
+			}
+		})
+	}
+}
@@ -10,15 +10,24 @@ import (
 	"time"
 
 	"github.com/miniscruff/dashy/configs"
+	"github.com/miniscruff/dashy/metrics"
+	"github.com/miniscruff/dashy/notify"
 	"github.com/miniscruff/dashy/store"
 )
 
 type Server struct {
-	StaticFS  embed.FS
-	Config    *configs.Config
-	Store     store.Store
+	StaticFS embed.FS
+	Config   *configs.Config
+	Store    store.Store
 
-	indexFile []byte
+	// indexFiles are the generated dashboards by path
+	indexFiles map[string][]byte
+	notifiers  map[string]notify.Notifier
+	events     eventHub
+	databases  databasePool
+	redises    redisPool
+	limits     hostLimiter
+	schemas    schemaCache
 }
 
 func (s *Server) StaticFileHandler(w http.ResponseWriter, r *http.Request) {
@@ -40,14 +49,28 @@ func (s *Server) StaticFileHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) Serve() error {
+	if err := s.buildNotifiers(); err != nil {
+		return err
+	}
+	s.limits.configure(s.Config.RateLimits)
+
+	// pages are built before listening so broken dashboards fail at startup
+	if err := s.GenerateIndex(); err != nil {
+		return fmt.Errorf("unable to generate index: %w", err)
+	}
+
 	// hook up handlers
-	http.HandleFunc("/api/checkFeeds", s.CheckFeedHandler)
-	http.HandleFunc("/api/checkFeed/", s.CheckFeedHandler)
-	http.HandleFunc("/api/updateFeed/", s.UpdateFeedHandler)
-	http.HandleFunc("/api/values", s.ValuesHandler)
-	http.HandleFunc("/api/events", s.EventsHandler)
-	http.HandleFunc("/static/", s.StaticFileHandler)
-	http.HandleFunc("/", s.IndexHandler)
+	http.HandleFunc("/api/checkFeeds", metrics.InstrumentHandler("checkFeeds", s.CheckFeedHandler))
+	http.HandleFunc("/api/checkFeed/", metrics.InstrumentHandler("checkFeed", s.CheckFeedHandler))
+	http.HandleFunc("/api/updateFeed/", metrics.InstrumentHandler("updateFeed", s.UpdateFeedHandler))
+	http.HandleFunc("/api/push/", metrics.InstrumentHandler("push", s.PushFeedHandler))
+	http.HandleFunc("/api/values", metrics.InstrumentHandler("values", s.ValuesHandler))
+	http.HandleFunc("/api/status", metrics.InstrumentHandler("status", s.StatusHandler))
+	http.HandleFunc("/api/events", metrics.InstrumentHandler("events", s.EventsHandler))
+	http.HandleFunc("/static/", metrics.InstrumentHandler("static", s.StaticFileHandler))
+	http.HandleFunc("/d/", metrics.InstrumentHandler("dashboard", s.DashboardHandler))
+	http.HandleFunc("/", metrics.InstrumentHandler("index", s.IndexHandler))
+	http.Handle("/metrics", metrics.Handler())
 
 	// auto tick every 5 minutes
 	ticker := time.NewTicker(s.Config.Env.TickDuration)
@@ -61,7 +84,6 @@ func (s *Server) Serve() error {
 	}()
 	// run at startup as well
 	go s.CheckAllFeeds()
-	go s.GenerateIndex()
 
 	host := fmt.Sprintf("%v:%v", s.Config.Env.Address, s.Config.Env.Port)
 	log.Println("listening on", host)
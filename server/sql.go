@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	// sql drivers available to sql feeds
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+const (
+	defaultSQLTimeout      = 30 * time.Second
+	defaultSQLMaxOpenConns = 2
+)
+
+// databasePool shares connection pools between feeds using the same
+// driver and dsn, the zero value is ready to use.
+type databasePool struct {
+	mu  sync.Mutex
+	dbs map[string]*sql.DB
+}
+
+func (p *databasePool) open(driver, dsn string, maxOpenConns int) (*sql.DB, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := driver + "|" + dsn
+	if db, exists := p.dbs[key]; exists {
+		return db, nil
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxOpenConns <= 0 {
+		maxOpenConns = defaultSQLMaxOpenConns
+	}
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxOpenConns)
+	db.SetConnMaxIdleTime(5 * time.Minute)
+
+	if p.dbs == nil {
+		p.dbs = make(map[string]*sql.DB)
+	}
+	p.dbs[key] = db
+	return db, nil
+}
+
+func (s *Server) fetchSQL(feed *configs.FeedConfig) ([]byte, error) {
+	config := feed.SQL
+	tmpl := s.newQueryTemplate(feed)
+
+	query, err := tmpl.Render("query", config.Query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render query: %w", err)
+	}
+
+	args := make([]interface{}, len(config.Args))
+	for i, arg := range config.Args {
+		rendered, err := tmpl.Render(fmt.Sprintf("arg%v", i), arg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render arg %v: %w", i, err)
+		}
+		args[i] = s.Store.StringOrVar(rendered)
+	}
+
+	db, err := s.databases.open(config.Driver, s.Store.StringOrVar(config.DSN), config.MaxOpenConns)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open database: %w", err)
+	}
+
+	timeout := defaultSQLTimeout
+	if config.TimeoutDuration > 0 {
+		timeout = config.TimeoutDuration
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to run query: %w", err)
+	}
+	defer rows.Close()
+
+	return sqlDocument(rows)
+}
+
+// sqlDocument reads all rows into json with the first value and row,
+// all rows and all values of each column.
+func sqlDocument(rows *sql.Rows) ([]byte, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	allRows := []map[string]interface{}{}
+	allColumns := make(map[string][]interface{}, len(columns))
+	for _, c := range columns {
+		allColumns[c] = []interface{}{}
+	}
+
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("unable to scan row: %w", err)
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, c := range columns {
+			value := values[i]
+			if b, ok := value.([]byte); ok {
+				value = string(b)
+			}
+			row[c] = value
+			allColumns[c] = append(allColumns[c], value)
+		}
+		allRows = append(allRows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read rows: %w", err)
+	}
+
+	doc := map[string]interface{}{
+		"value":   nil,
+		"row":     nil,
+		"rows":    allRows,
+		"columns": allColumns,
+	}
+	if len(allRows) > 0 && len(columns) > 0 {
+		doc["value"] = allRows[0][columns[0]]
+		doc["row"] = allRows[0]
+	}
+
+	return json.Marshal(doc)
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+func newTestDatabase(t *testing.T) string {
+	t.Helper()
+
+	dsn := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatalf("unable to open database: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE jobs (name TEXT, status TEXT, runs INTEGER);
+		INSERT INTO jobs VALUES ('build', 'ok', 3), ('deploy', 'failed', 1);
+	`)
+	if err != nil {
+		t.Fatalf("unable to create table: %v", err)
+	}
+
+	return dsn
+}
+
+func TestSqlDocument(t *testing.T) {
+	dsn := newTestDatabase(t)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatalf("unable to open database: %v", err)
+	}
+	defer db.Close()
+
+	for _, tc := range []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:  "rows",
+			query: "SELECT name, runs FROM jobs ORDER BY name",
+			expected: `{"columns":{"name":["build","deploy"],"runs":[3,1]},` +
+				`"row":{"name":"build","runs":3},` +
+				`"rows":[{"name":"build","runs":3},{"name":"deploy","runs":1}],` +
+				`"value":"build"}`,
+		},
+		{
+			name:     "empty",
+			query:    "SELECT name FROM jobs WHERE runs > 10",
+			expected: `{"columns":{"name":[]},"row":null,"rows":[],"value":null}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, err := db.Query(tc.query)
+			if err != nil {
+				t.Fatalf("unable to query: %v", err)
+			}
+			defer rows.Close()
+
+			doc, err := sqlDocument(rows)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(doc) != tc.expected {
+				t.Errorf("expected %v\ngot %v", tc.expected, string(doc))
+			}
+		})
+	}
+}
+
+func TestFetchSQL(t *testing.T) {
+	dsn := newTestDatabase(t)
+
+	for _, tc := range []struct {
+		name     string
+		config   configs.FeedSQL
+		vars     map[string]string
+		expected string
+	}{
+		{
+			name: "count",
+			config: configs.FeedSQL{
+				Query: "SELECT COUNT(*) FROM jobs",
+			},
+			expected: "2",
+		},
+		{
+			name: "bound args",
+			config: configs.FeedSQL{
+				Query: "SELECT runs FROM jobs WHERE name = ?",
+				Args:  []string{"{{.name}}"},
+			},
+			vars:     map[string]string{"name": "deploy"},
+			expected: "1",
+		},
+		{
+			name: "args are not spliced into the query",
+			config: configs.FeedSQL{
+				Query: "SELECT COUNT(*) FROM jobs WHERE name = ?",
+				Args:  []string{"{{.name}}"},
+			},
+			vars:     map[string]string{"name": "x' OR '1'='1"},
+			expected: "0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config := tc.config
+			config.Driver = "sqlite3"
+			config.DSN = dsn
+
+			s := &Server{Store: newMemoryStore()}
+			feed := &configs.FeedConfig{Name: "jobs", Type: "sql", SQL: &config, Vars: tc.vars}
+
+			body, err := s.fetchSQL(feed)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if value := gjson.GetBytes(body, "value").Raw; value != tc.expected {
+				t.Errorf("expected value '%v', got '%v'", tc.expected, value)
+			}
+		})
+	}
+}
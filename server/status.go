@@ -0,0 +1,116 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/miniscruff/dashy/configs"
+	"github.com/miniscruff/dashy/store"
+)
+
+// statusError is returned when a response does not match the expected
+// status codes, the body is kept for error stores.
+type statusError struct {
+	Code     int
+	Expected configs.StatusCodes
+	Body     []byte
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("status code '%v' does not match expected '%v'", e.Code, e.Expected)
+}
+
+func (s *Server) feedSucceeded(feed *configs.FeedConfig) {
+	err := s.Store.SetFeedStatus(feed, store.FeedStatus{
+		Healthy:   true,
+		CheckedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Println(fmt.Errorf("unable to set feed status: %w", err))
+	}
+}
+
+// feedFailed records the failure, clears the response cache and stores
+// any error values from the error response.
+func (s *Server) feedFailed(feed *configs.FeedConfig, failure error) {
+	status := store.FeedStatus{
+		Healthy:   false,
+		Error:     failure.Error(),
+		CheckedAt: time.Now().UTC(),
+	}
+
+	var assertErr *assertionError
+	if errors.As(failure, &assertErr) {
+		status.Path = assertErr.Path
+		log.Printf("feed '%v' failed assertion at '%v'\n", feed.Name, assertErr.Path)
+	}
+
+	// clear the cache so a response that failed is fetched and checked
+	// again instead of being skipped as not modified
+	if err := s.Store.SetResponseCache(feed, store.ResponseCache{}); err != nil {
+		log.Println(fmt.Errorf("unable to clear response cache: %w", err))
+	}
+
+	if onError := feed.Query.OnError; onError != nil {
+		status.Stale = onError.MarkStale
+
+		var statusErr *statusError
+		if errors.As(failure, &statusErr) && len(onError.Store) > 0 && gjson.ValidBytes(statusErr.Body) {
+			results := extractValues(onError.Store, statusErr.Body)
+			err := transformValues(onError.Store, results)
+			if err == nil {
+				err = s.Store.SetValues(feed, results)
+			}
+			if err != nil {
+				log.Println(fmt.Errorf("unable to store error values: %w", err))
+			}
+			s.PublishValues()
+		}
+	}
+
+	if err := s.Store.SetFeedStatus(feed, status); err != nil {
+		log.Println(fmt.Errorf("unable to set feed status: %w", err))
+	}
+}
+
+// clearErrorValues adds null results for error stores so a success
+// replaces values from the last error.
+func clearErrorValues(feed *configs.FeedConfig, results map[string]gjson.Result) {
+	if feed.Query.OnError == nil {
+		return
+	}
+
+	for _, es := range feed.Query.OnError.Store {
+		results[es.Name] = gjson.Parse("null")
+	}
+}
+
+func (s *Server) StatusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	statuses, err := s.Store.GetFeedStatuses()
+	if err != nil {
+		log.Println(fmt.Errorf("unable to get feed statuses: %w", err))
+		http.Error(w, "unable to get feed statuses", 500)
+		return
+	}
+
+	jsonData, err := json.Marshal(statuses)
+	if err != nil {
+		log.Println(fmt.Errorf("unable to marshal feed statuses: %w", err))
+		http.Error(w, "unable to marshal feed statuses", 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/miniscruff/dashy/configs"
+	"github.com/miniscruff/dashy/store"
+)
+
+// memoryStore is an in memory store for tests
+type memoryStore struct {
+	mu       sync.Mutex
+	values   map[string]map[string]interface{}
+	updated  map[string]map[string]time.Time
+	alerts   map[string]store.AlertState
+	caches   map[string]store.ResponseCache
+	statuses map[string]store.FeedStatus
+	nextRuns map[string]time.Time
+}
+
+var _ store.Store = (*memoryStore)(nil)
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		values:   make(map[string]map[string]interface{}),
+		updated:  make(map[string]map[string]time.Time),
+		alerts:   make(map[string]store.AlertState),
+		caches:   make(map[string]store.ResponseCache),
+		statuses: make(map[string]store.FeedStatus),
+		nextRuns: make(map[string]time.Time),
+	}
+}
+
+func (m *memoryStore) StringOrVar(value string) string {
+	if strings.HasPrefix(value, "env:") {
+		return os.Getenv(value[4:])
+	}
+	return value
+}
+
+func (m *memoryStore) GetNextRun(feed *configs.FeedConfig) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nextRuns[feed.Name], nil
+}
+
+func (m *memoryStore) SetNextRun(feed *configs.FeedConfig, nextRun time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextRuns[feed.Name] = nextRun
+	return nil
+}
+
+func (m *memoryStore) GetValues() (map[string]map[string]interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	values := make(map[string]map[string]interface{}, len(m.values))
+	for feed, stores := range m.values {
+		values[feed] = make(map[string]interface{}, len(stores))
+		for name, value := range stores {
+			values[feed][name] = value
+		}
+	}
+	return values, nil
+}
+
+func (m *memoryStore) SetValues(feed *configs.FeedConfig, values map[string]gjson.Result) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.values[feed.Name] == nil {
+		m.values[feed.Name] = make(map[string]interface{})
+		m.updated[feed.Name] = make(map[string]time.Time)
+	}
+	for name, result := range values {
+		m.values[feed.Name][name] = result.Value()
+		m.updated[feed.Name][name] = time.Now().UTC()
+	}
+	return nil
+}
+
+func (m *memoryStore) GetUpdated() (map[string]map[string]time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updated, nil
+}
+
+func (m *memoryStore) TouchUpdated(feed *configs.FeedConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.updated[feed.Name] == nil {
+		m.updated[feed.Name] = make(map[string]time.Time)
+	}
+	for _, s := range feed.Store {
+		m.updated[feed.Name][s.Name] = time.Now().UTC()
+	}
+	return nil
+}
+
+func (m *memoryStore) GetAlertState(name string) (store.AlertState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.alerts[name], nil
+}
+
+func (m *memoryStore) SetAlertState(name string, state store.AlertState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.alerts[name] = state
+	return nil
+}
+
+func (m *memoryStore) GetResponseCache(feed *configs.FeedConfig) (store.ResponseCache, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.caches[feed.Name], nil
+}
+
+func (m *memoryStore) SetResponseCache(feed *configs.FeedConfig, cache store.ResponseCache) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.caches[feed.Name] = cache
+	return nil
+}
+
+func (m *memoryStore) GetFeedStatuses() (map[string]store.FeedStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.statuses, nil
+}
+
+func (m *memoryStore) SetFeedStatus(feed *configs.FeedConfig, status store.FeedStatus) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statuses[feed.Name] = status
+	return nil
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// loadStyleFiles reads raw css files, paths starting with `static:`
+// are read from the static files and anything else from disk.
+func (s *Server) loadStyleFiles(paths []string) ([]string, error) {
+	styles := make([]string, 0, len(paths))
+	for _, path := range paths {
+		var (
+			css []byte
+			err error
+		)
+
+		if strings.HasPrefix(path, "static:") {
+			css, err = s.StaticFS.ReadFile("resources/static/" + path[7:])
+		} else {
+			css, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read style file '%v': %w", path, err)
+		}
+
+		styles = append(styles, string(css))
+	}
+	return styles, nil
+}
+
+// writeStyleRules writes css rules sorted by selector so the output is stable
+func writeStyleRules(builder *strings.Builder, rules map[string]string) {
+	selectors := make([]string, 0, len(rules))
+	for selector := range rules {
+		selectors = append(selectors, selector)
+	}
+	sort.Strings(selectors)
+
+	for _, selector := range selectors {
+		builder.WriteString(fmt.Sprintf("%v {%v}", selector, rules[selector]))
+	}
+}
+
+// styleAttr creates an inline style attribute, empty when there is no css
+func styleAttr(css string) string {
+	if css == "" {
+		return ""
+	}
+	return fmt.Sprintf(` style="%v"`, strings.ReplaceAll(css, `"`, "&quot;"))
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+// queryTemplate renders query values with access to other feeds values
+// and forEach variables as `{{.var}}`, values are only loaded from the store
+// when a template asks for them.
+//
+// Available functions:
+//
+//	feeds:  stored values of all feeds, `{{feeds.repos.latestId}}`
+//	secret: any config value such as `{{secret "env:TOKEN"}}`
+//	env:    environment variable, `{{env "TOKEN"}}`
+//	now:    current time in UTC, `{{now.Format "2006-01-02"}}`
+//	ago:    current time minus a duration, days are allowed, `{{ago "7d"}}`
+//	iso:    RFC3339 format of a time, `{{iso (ago "24h")}}`
+//	unix:   unix seconds of a time, `{{unix now}}`
+//	json:   json encoding of any value, `{{json feeds.repos.names}}`
+type queryTemplate struct {
+	server *Server
+	vars   map[string]string
+	values map[string]map[string]interface{}
+}
+
+func (s *Server) newQueryTemplate(feed *configs.FeedConfig) *queryTemplate {
+	return &queryTemplate{
+		server: s,
+		vars:   feed.Vars,
+	}
+}
+
+func (t *queryTemplate) feeds() (map[string]map[string]interface{}, error) {
+	if t.values != nil {
+		return t.values, nil
+	}
+
+	values, err := t.server.Store.GetValues()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stores := range values {
+		for name, value := range stores {
+			stores[name] = templateValue(value)
+		}
+	}
+
+	t.values = values
+	return values, nil
+}
+
+// templateValue converts numbers to json numbers so they render as plain
+// decimals such as 12345678 instead of 1.2345678e+07.
+func templateValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		return json.Number(strconv.FormatFloat(v, 'f', -1, 64))
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, inner := range v {
+			converted[i] = templateValue(inner)
+		}
+		return converted
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for k, inner := range v {
+			converted[k] = templateValue(inner)
+		}
+		return converted
+	}
+	return value
+}
+
+func (t *queryTemplate) Render(name, text string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New(name).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"feeds":  t.feeds,
+			"secret": t.server.Store.StringOrVar,
+			"env":    os.Getenv,
+			"now":    now,
+			"ago":    ago,
+			"iso":    iso,
+			"unix":   unix,
+			"json":   toJson,
+		}).
+		Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, t.vars); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+func ago(duration string) (time.Time, error) {
+	dur, err := parseDuration(duration)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return now().Add(-dur), nil
+}
+
+// parseDuration extends time.ParseDuration with whole days such as "7d"
+func parseDuration(duration string) (time.Duration, error) {
+	if strings.HasSuffix(duration, "d") {
+		days, err := strconv.Atoi(duration[:len(duration)-1])
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(duration)
+}
+
+func iso(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+func unix(t time.Time) int64 {
+	return t.Unix()
+}
+
+func toJson(value interface{}) (string, error) {
+	bytes, err := json.Marshal(value)
+	return string(bytes), err
+}
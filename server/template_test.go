@@ -0,0 +1,143 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+func TestRenderNumbers(t *testing.T) {
+	memory := newMemoryStore()
+	memory.SetValues(&configs.FeedConfig{Name: "repos"}, map[string]gjson.Result{
+		"big":   gjson.Parse("12345678"),
+		"small": gjson.Parse("0.000001"),
+		"ids":   gjson.Parse("[10000000,2.5]"),
+	})
+	s := &Server{Store: memory}
+
+	for _, tc := range []struct {
+		text     string
+		expected string
+	}{
+		{text: "{{feeds.repos.big}}", expected: "12345678"},
+		{text: "{{feeds.repos.small}}", expected: "0.000001"},
+		{text: "{{range feeds.repos.ids}}{{.}},{{end}}", expected: "10000000,2.5,"},
+		{text: "{{json feeds.repos.ids}}", expected: "[10000000,2.5]"},
+	} {
+		t.Run(tc.text, func(t *testing.T) {
+			tmpl := s.newQueryTemplate(&configs.FeedConfig{Name: "details"})
+			got, err := tmpl.Render("url", tc.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Setenv("DASHY_TOKEN", "abc123")
+
+	memory := newMemoryStore()
+	memory.SetValues(&configs.FeedConfig{Name: "repos"}, map[string]gjson.Result{
+		"latest": gjson.Parse(`"dashy"`),
+		"names":  gjson.Parse(`["a","b"]`),
+	})
+	s := &Server{Store: memory}
+
+	for _, tc := range []struct {
+		name     string
+		text     string
+		expected string
+		fails    bool
+	}{
+		{name: "plain", text: "https://example.com", expected: "https://example.com"},
+		{name: "vars", text: "/repos/{{.repo}}", expected: "/repos/go-redis"},
+		{name: "feeds", text: "/repos/{{feeds.repos.latest}}", expected: "/repos/dashy"},
+		{name: "json", text: "{{json feeds.repos.names}}", expected: `["a","b"]`},
+		{name: "secret", text: `Bearer {{secret "env:DASHY_TOKEN"}}`, expected: "Bearer abc123"},
+		{name: "secret text", text: `{{secret "plain"}}`, expected: "plain"},
+		{name: "env", text: `{{env "DASHY_TOKEN"}}`, expected: "abc123"},
+		{name: "missing var", text: "{{.missing}}", fails: true},
+		{name: "missing feed", text: "{{feeds.missing.latest}}", fails: true},
+		{name: "missing store", text: "{{feeds.repos.missing}}", fails: true},
+		{name: "bad duration", text: `{{ago "xd"}}`, fails: true},
+		{name: "bad template", text: "{{.repo", fails: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl := s.newQueryTemplate(&configs.FeedConfig{
+				Name: "details",
+				Vars: map[string]string{"repo": "go-redis"},
+			})
+
+			got, err := tmpl.Render(tc.name, tc.text)
+			if tc.fails {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRenderAgo(t *testing.T) {
+	s := &Server{Store: newMemoryStore()}
+	tmpl := s.newQueryTemplate(&configs.FeedConfig{Name: "issues"})
+
+	got, err := tmpl.Render("since", `{{iso (ago "7d")}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	since, err := time.Parse(time.RFC3339, got)
+	if err != nil {
+		t.Fatalf("expected an RFC3339 time, got %v", got)
+	}
+	if diff := time.Since(since) - 7*24*time.Hour; diff < -time.Second || diff > time.Minute {
+		t.Errorf("expected 7 days ago, got %v", got)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for _, tc := range []struct {
+		duration string
+		expected time.Duration
+		fails    bool
+	}{
+		{duration: "7d", expected: 7 * 24 * time.Hour},
+		{duration: "0d", expected: 0},
+		{duration: "90m", expected: 90 * time.Minute},
+		{duration: "1h30m", expected: 90 * time.Minute},
+		{duration: "d", fails: true},
+		{duration: "1.5d", fails: true},
+		{duration: "soon", fails: true},
+	} {
+		t.Run(tc.duration, func(t *testing.T) {
+			got, err := parseDuration(tc.duration)
+			if tc.fails {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
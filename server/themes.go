@@ -0,0 +1,98 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+// themeColors are the resolved css variables of a dashboard theme
+type themeColors struct {
+	dark   map[string]string
+	light  map[string]string
+	toggle bool
+}
+
+func (s *Server) resolveTheme(theme configs.ThemeConfig) (themeColors, error) {
+	var (
+		colors themeColors
+		err    error
+	)
+
+	if colors.dark, err = s.Config.ThemeColors(theme.DarkTheme()); err != nil {
+		return colors, err
+	}
+
+	if theme.Light != "" {
+		if colors.light, err = s.Config.ThemeColors(theme.Light); err != nil {
+			return colors, err
+		}
+	}
+
+	colors.toggle = theme.Toggle
+	return colors, nil
+}
+
+func themeVariables(colors map[string]string) string {
+	names := make([]string, 0, len(colors))
+	for name := range colors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder strings.Builder
+	for _, name := range names {
+		builder.WriteString(fmt.Sprintf("--%v: %v;", name, colors[name]))
+	}
+	return builder.String()
+}
+
+// buildTheme creates the theme variables, the light theme is used when
+// preferred by the browser unless the toggle picked a theme.
+func (b *IndexBuilder) buildTheme() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf(":root {%v}", themeVariables(b.theme.dark)))
+
+	if b.theme.light == nil {
+		return builder.String()
+	}
+
+	builder.WriteString(fmt.Sprintf(
+		"@media (prefers-color-scheme: light) {:root {%v}}",
+		themeVariables(b.theme.light),
+	))
+
+	if b.theme.toggle {
+		builder.WriteString(fmt.Sprintf(
+			`:root[data-theme="dark"] {%v}:root[data-theme="light"] {%v}`,
+			themeVariables(b.theme.dark),
+			themeVariables(b.theme.light),
+		))
+	}
+
+	return builder.String()
+}
+
+func (b *IndexBuilder) buildThemeToggle() string {
+	if !b.theme.toggle {
+		return ""
+	}
+	return `<button class="theme-toggle" title="Toggle theme" onclick="toggleTheme()">◐</button>`
+}
+
+// themeScripts apply the saved toggle choice and switch themes on click
+const themeScripts = `
+	const savedTheme = localStorage.getItem('theme');
+	if (savedTheme) {
+		document.documentElement.dataset.theme = savedTheme;
+	}
+	function toggleTheme() {
+		const current = document.documentElement.dataset.theme ||
+			(window.matchMedia('(prefers-color-scheme: light)').matches ? 'light' : 'dark');
+		const next = current === 'light' ? 'dark' : 'light';
+		document.documentElement.dataset.theme = next;
+		localStorage.setItem('theme', next);
+	}
+	`
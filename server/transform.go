@@ -0,0 +1,218 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+// transformValues runs each store transform pipeline on its extracted value,
+// results are converted back to json so number, bool and array types remain.
+func transformValues(stores []configs.FeedStore, results map[string]gjson.Result) error {
+	for _, store := range stores {
+		if len(store.Transform) == 0 {
+			continue
+		}
+
+		value := results[store.Name].Value()
+		for _, t := range store.Transform {
+			var err error
+			if value, err = applyTransform(t, value); err != nil {
+				return fmt.Errorf("unable to transform '%v' with '%v': %w", store.Name, t.Op, err)
+			}
+		}
+
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("unable to marshal '%v': %w", store.Name, err)
+		}
+		results[store.Name] = gjson.ParseBytes(raw)
+	}
+
+	return nil
+}
+
+func applyTransform(t configs.Transform, value interface{}) (interface{}, error) {
+	switch t.Op {
+	case "filter":
+		items, ok := value.([]interface{})
+		if !ok {
+			return value, nil
+		}
+		return filterItems(t, items), nil
+	case "sort":
+		return sortItems(t, value), nil
+	case "limit":
+		items, ok := value.([]interface{})
+		if !ok {
+			return value, nil
+		}
+		limit := int(t.Number)
+		if limit < 0 {
+			limit = 0
+		}
+		if limit < len(items) {
+			return items[:limit], nil
+		}
+		return items, nil
+	case "reverse":
+		items, ok := value.([]interface{})
+		if !ok {
+			return value, nil
+		}
+		reversed := make([]interface{}, len(items))
+		for i, item := range items {
+			reversed[len(items)-1-i] = item
+		}
+		return reversed, nil
+	case "default":
+		if value == nil {
+			return t.Value, nil
+		}
+		return value, nil
+	}
+
+	// remaining transforms apply to every item of arrays
+	if items, ok := value.([]interface{}); ok {
+		transformed := make([]interface{}, len(items))
+		for i, item := range items {
+			var err error
+			if transformed[i], err = transformItem(t, item); err != nil {
+				return nil, err
+			}
+		}
+		return transformed, nil
+	}
+
+	return transformItem(t, value)
+}
+
+func transformItem(t configs.Transform, value interface{}) (interface{}, error) {
+	switch t.Op {
+	case "add", "subtract", "multiply", "divide", "round", "number":
+		f, ok := configs.ToFloat(value)
+		if !ok {
+			return nil, fmt.Errorf("'%v' is not a number", value)
+		}
+
+		switch t.Op {
+		case "add":
+			return f + t.Number, nil
+		case "subtract":
+			return f - t.Number, nil
+		case "multiply":
+			return f * t.Number, nil
+		case "divide":
+			return f / t.Number, nil
+		case "round":
+			scale := math.Pow(10, t.Number)
+			return math.Round(f*scale) / scale, nil
+		}
+		return f, nil
+	case "trim":
+		return strings.TrimSpace(toText(value)), nil
+	case "upper":
+		return strings.ToUpper(toText(value)), nil
+	case "lower":
+		return strings.ToLower(toText(value)), nil
+	case "replace":
+		return strings.ReplaceAll(toText(value), t.Replace.Old, t.Replace.New), nil
+	case "regex":
+		match := t.Pattern.FindStringSubmatch(toText(value))
+		if match == nil {
+			return nil, nil
+		}
+		if len(match) > 1 {
+			return match[1], nil
+		}
+		return match[0], nil
+	case "date":
+		return transformDate(t.Date, value)
+	case "map":
+		if label, exists := t.Labels[toText(value)]; exists {
+			return label, nil
+		}
+		if label, exists := t.Labels["_"]; exists {
+			return label, nil
+		}
+		return value, nil
+	}
+
+	return value, nil
+}
+
+func transformDate(date configs.TransformDate, value interface{}) (interface{}, error) {
+	var parsed time.Time
+	if date.From == "unix" {
+		seconds, ok := configs.ToFloat(value)
+		if !ok {
+			return nil, fmt.Errorf("'%v' is not a unix time", value)
+		}
+		parsed = time.Unix(int64(seconds), 0).UTC()
+	} else {
+		var err error
+		if parsed, err = time.Parse(date.From, toText(value)); err != nil {
+			return nil, err
+		}
+	}
+
+	if date.To == "unix" {
+		return parsed.Unix(), nil
+	}
+	return parsed.Format(date.To), nil
+}
+
+func filterItems(t configs.Transform, items []interface{}) []interface{} {
+	filtered := []interface{}{}
+	for _, item := range items {
+		if t.Filter.Matches(item) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+func sortItems(t configs.Transform, value interface{}) interface{} {
+	items, ok := value.([]interface{})
+	if !ok {
+		return value
+	}
+
+	sorted := make([]interface{}, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if t.Text == "desc" {
+			return lessItem(sorted[j], sorted[i])
+		}
+		return lessItem(sorted[i], sorted[j])
+	})
+	return sorted
+}
+
+// lessItem compares numbers numerically and anything else as text
+func lessItem(a, b interface{}) bool {
+	aNum, aOk := configs.ToFloat(a)
+	bNum, bOk := configs.ToFloat(b)
+	if aOk && bOk {
+		return aNum < bNum
+	}
+	return toText(a) < toText(b)
+}
+
+func toText(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/tidwall/gjson"
+	"gopkg.in/yaml.v2"
+
+	"github.com/miniscruff/dashy/configs"
+)
+
+func parseTransforms(t *testing.T, raw string) []configs.Transform {
+	t.Helper()
+	var transforms []configs.Transform
+	if err := yaml.Unmarshal([]byte(raw), &transforms); err != nil {
+		t.Fatalf("unable to parse transforms: %v", err)
+	}
+	return transforms
+}
+
+func TestTransformValues(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		transform string
+		body      string
+		expected  string
+		fails     bool
+	}{
+		{name: "multiply", transform: "[multiply: 1.8, add: 32]", body: `{"v":100}`, expected: "212"},
+		{name: "round", transform: "[round: 1]", body: `{"v":1.26}`, expected: "1.3"},
+		{name: "number", transform: "[number: true]", body: `{"v":" 42 "}`, expected: "42"},
+		{name: "not a number", transform: "[add: 1]", body: `{"v":"high"}`, fails: true},
+		{name: "strings", transform: "[trim: true, upper: true]", body: `{"v":" up "}`, expected: `"UP"`},
+		{name: "replace", transform: "[replace: {old: _, new: ' '}]", body: `{"v":"a_b"}`, expected: `"a b"`},
+		{name: "regex group", transform: `[regex: "(\\d+)%"]`, body: `{"v":"cpu 42%"}`, expected: `"42"`},
+		{name: "regex no match", transform: `[regex: "\\d+"]`, body: `{"v":"cpu"}`, expected: "null"},
+		{name: "unix date", transform: "[date: {from: unix, to: '2006-01-02'}]", body: `{"v":86400}`, expected: `"1970-01-02"`},
+		{name: "to unix", transform: "[date: {to: unix}]", body: `{"v":"1970-01-02T00:00:00Z"}`, expected: "86400"},
+		{name: "bad date", transform: "[date: {}]", body: `{"v":"yesterday"}`, fails: true},
+		{name: "map", transform: "[map: {up: online, _: offline}]", body: `{"v":"down"}`, expected: `"offline"`},
+		{name: "default", transform: "[default: 0]", body: `{}`, expected: "0"},
+		{name: "array items", transform: "[multiply: 2]", body: `{"v":[1,"2"]}`, expected: "[2,4]"},
+		{name: "filter", transform: "[filter: value > 1]", body: `{"v":[1,2,3]}`, expected: "[2,3]"},
+		{name: "filter empty", transform: "[filter: value > 5]", body: `{"v":[1,2]}`, expected: "[]"},
+		{name: "sort desc", transform: "[sort: desc]", body: `{"v":[2,10,1]}`, expected: "[10,2,1]"},
+		{name: "sort text", transform: "[sort: asc]", body: `{"v":["b","a","c"]}`, expected: `["a","b","c"]`},
+		{name: "limit", transform: "[limit: 2]", body: `{"v":[1,2,3]}`, expected: "[1,2]"},
+		{name: "limit scalar", transform: "[limit: 2]", body: `{"v":3}`, expected: "3"},
+		{name: "filter scalar", transform: "[filter: value > 1]", body: `{"v":3}`, expected: "3"},
+		{name: "reverse", transform: "[reverse: true]", body: `{"v":[1,2,3]}`, expected: "[3,2,1]"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stores := []configs.FeedStore{{
+				Name:      "value",
+				Path:      "v",
+				Transform: parseTransforms(t, tc.transform),
+			}}
+			results := map[string]gjson.Result{"value": gjson.Get(tc.body, "v")}
+
+			err := transformValues(stores, results)
+			if tc.fails {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", results["value"].Raw)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := results["value"].Raw; got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestTransformValuesSkipsStores(t *testing.T) {
+	stores := []configs.FeedStore{{Name: "value", Path: "v"}}
+	results := map[string]gjson.Result{"value": gjson.Get(`{"v":"1.50"}`, "v")}
+
+	if err := transformValues(stores, results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := results["value"].Raw; got != `"1.50"` {
+		t.Errorf("expected the value to be untouched, got %v", got)
+	}
+}
+
+func TestApplyTransformNegativeLimit(t *testing.T) {
+	value, err := applyTransform(configs.Transform{Op: "limit", Number: -1}, []interface{}{1.0, 2.0})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if items := value.([]interface{}); len(items) != 0 {
+		t.Errorf("expected no items, got %v", items)
+	}
+}
@@ -7,10 +7,13 @@ import (
 	"log"
 	"net/http"
 	"net/url"
-	"time"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/miniscruff/dashy/configs"
+	"github.com/miniscruff/dashy/metrics"
+	"github.com/miniscruff/dashy/store"
 	"github.com/tidwall/gjson"
 )
 
@@ -41,13 +44,37 @@ func (s *Server) UpdateFeedHandler(w http.ResponseWriter, r *http.Request) {
 func (s *Server) UpdateFeed(feed *configs.FeedConfig) error {
 	log.Printf("updating feed: %v\n", feed.Name)
 
+	start := time.Now()
 	results, err := s.fetch(feed)
+	metrics.FeedFetchDuration.WithLabelValues(feed.Name).Observe(time.Since(start).Seconds())
+	if errors.Is(err, errNotModified) {
+		metrics.FeedFetches.WithLabelValues(feed.Name, "success").Inc()
+		metrics.FeedLastSuccess.WithLabelValues(feed.Name).SetToCurrentTime()
+		s.feedSucceeded(feed)
+		log.Printf("feed not modified: %v\n", feed.Name)
+
+		// the values were confirmed so they are not stale
+		if err := s.Store.TouchUpdated(feed); err != nil {
+			log.Println(fmt.Errorf("unable to touch updated times: %w", err))
+		}
+		// alerts may still be due to resend or retry failed notifiers
+		s.CheckAlerts(feed)
+		s.PublishValues()
+
+		return s.updateNextRun(feed)
+	}
 	if err != nil {
+		metrics.FeedFetches.WithLabelValues(feed.Name, "failure").Inc()
+		s.feedFailed(feed, err)
 		return fmt.Errorf("unable to fetch data: %w", err)
 	}
+	metrics.FeedFetches.WithLabelValues(feed.Name, "success").Inc()
 
+	clearErrorValues(feed, results)
 	err = s.Store.SetValues(feed, results)
 	if err != nil {
+		// clear the cache so the next fetch is not skipped as not modified
+		_ = s.Store.SetResponseCache(feed, store.ResponseCache{})
 		return fmt.Errorf("unable to store data: %w", err)
 	}
 
@@ -56,77 +83,221 @@ func (s *Server) UpdateFeed(feed *configs.FeedConfig) error {
 		return fmt.Errorf("unable to update next run: %w", err)
 	}
 
+	metrics.FeedLastSuccess.WithLabelValues(feed.Name).SetToCurrentTime()
+	s.feedSucceeded(feed)
+	s.CheckAlerts(feed)
+	s.PublishValues()
+
 	log.Printf("feed updated: %v\n", feed.Name)
 	return nil
 }
 
-func (s *Server) request(query *configs.FeedQuery) (*http.Request, error) {
-	bodyReader := strings.NewReader(query.Body)
+func (s *Server) request(feed *configs.FeedConfig) (*http.Request, error) {
+	query := &feed.Query
+	tmpl := s.newQueryTemplate(feed)
 
-	queryUrl := query.Url
+	body, err := tmpl.Render("body", query.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render body: %w", err)
+	}
+
+	method := query.Method
+	if query.GraphQL != nil {
+		if body, err = s.graphqlBody(tmpl, query.GraphQL); err != nil {
+			return nil, err
+		}
+		if method == "" {
+			method = http.MethodPost
+		}
+	}
+	bodyReader := strings.NewReader(body)
+
+	queryUrl, err := tmpl.Render("url", query.Url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render url: %w", err)
+	}
 
 	params := url.Values{}
 	for k, v := range query.Params {
-		params.Set(k, url.QueryEscape(s.Store.StringOrVar(v)))
+		param, err := tmpl.Render(k, v)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render param '%v': %w", k, err)
+		}
+		params.Set(k, s.Store.StringOrVar(param))
 	}
 
 	if len(params) > 0 {
 		queryUrl += "?" + params.Encode()
 	}
 
-	req, err := http.NewRequest(query.Method, queryUrl, bodyReader)
+	req, err := http.NewRequest(method, queryUrl, bodyReader)
 	if err != nil {
 		return nil, err
 	}
 
 	for k, v := range query.Headers {
-		req.Header.Add(k, s.Store.StringOrVar(v))
+		header, err := tmpl.Render(k, v)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render header '%v': %w", k, err)
+		}
+		req.Header.Add(k, s.Store.StringOrVar(header))
+	}
+
+	if query.GraphQL != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
 	}
 
 	return req, err
 }
 
+// errNotModified is returned when a conditional request finds no changes,
+// the feed is still considered updated.
+var errNotModified = errors.New("not modified")
+
+// FeedFetcher returns the json body of a feed for store paths to be applied to
+type FeedFetcher func(*configs.FeedConfig) ([]byte, error)
+
 func (s *Server) fetch(feed *configs.FeedConfig) (map[string]gjson.Result, error) {
+	var fetcher FeedFetcher
+	switch strings.ToLower(feed.Type) {
+	case "", "http":
+		fetcher = s.fetchHttp
+	case "prometheus":
+		fetcher = s.fetchPrometheus
+	case "command":
+		fetcher = s.fetchCommand
+	case "sql":
+		fetcher = s.fetchSQL
+	case "redis":
+		fetcher = s.fetchRedis
+	case "push":
+		return nil, errors.New("push feeds can not be fetched")
+	default:
+		return nil, fmt.Errorf("feed type '%v' not found", feed.Type)
+	}
+
+	bodyBytes, err := fetcher(feed)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.assert(feed, bodyBytes); err != nil {
+		return nil, err
+	}
+
+	results := extractValues(feed.Store, bodyBytes)
+	if err := transformValues(feed.Store, results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *Server) fetchHttp(feed *configs.FeedConfig) ([]byte, error) {
 	// might need configs for clients later...
 	client := &http.Client{}
 
-	req, err := s.request(&feed.Query)
+	req, err := s.request(feed)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create request from query: %w", err)
 	}
 
+	if feed.Query.Pagination != nil {
+		setFirstPage(req, feed.Query.Pagination)
+	}
+
+	cache, err := s.Store.GetResponseCache(feed)
+	if err != nil {
+		log.Println(fmt.Errorf("unable to get response cache: %w", err))
+	}
+	if cache.ETag != "" {
+		req.Header.Set("If-None-Match", cache.ETag)
+	}
+	if cache.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cache.LastModified)
+	}
+
+	bodyBytes, header, err := s.fetchPage(client, req, feed)
+	if err != nil {
+		return nil, err
+	}
+	newCache := store.ResponseCache{
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+	}
+
+	if feed.Query.GraphQL != nil {
+		if bodyBytes, err = graphqlData(bodyBytes); err != nil {
+			return nil, err
+		}
+	}
+
+	if feed.Query.Pagination != nil {
+		bodyBytes, err = s.fetchPages(client, req, feed, bodyBytes, header)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if newCache != cache {
+		if err := s.Store.SetResponseCache(feed, newCache); err != nil {
+			log.Println(fmt.Errorf("unable to set response cache: %w", err))
+		}
+	}
+
+	return bodyBytes, nil
+}
+
+func (s *Server) fetchPage(client *http.Client, req *http.Request, feed *configs.FeedConfig) ([]byte, http.Header, error) {
+	if err := s.limits.Take(req.URL.Hostname()); err != nil {
+		return nil, nil, fmt.Errorf("unable to request '%v': %w", req.URL.Hostname(), err)
+	}
+
 	res, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get response: %w", err)
+		return nil, nil, fmt.Errorf("unable to get response: %w", err)
 	}
+	defer res.Body.Close()
+	s.limits.Observe(req.URL.Hostname(), res)
 
-	if res.StatusCode != feed.Query.Status {
-		return nil, fmt.Errorf("status code '%v' does not match expected '%v'", res.StatusCode, feed.Query.Status)
+	metrics.FeedResponses.WithLabelValues(feed.Name, strconv.Itoa(res.StatusCode)).Inc()
+	if res.StatusCode == http.StatusNotModified {
+		return nil, nil, errNotModified
 	}
 
 	bodyBytes, err := io.ReadAll(res.Body)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read response bytes: %w", err)
+		return nil, nil, fmt.Errorf("unable to read response bytes: %w", err)
+	}
+
+	if !feed.Query.Status.Matches(res.StatusCode) {
+		return nil, nil, &statusError{
+			Code:     res.StatusCode,
+			Expected: feed.Query.Status,
+			Body:     bodyBytes,
+		}
 	}
-	defer res.Body.Close()
 
 	if !gjson.ValidBytes(bodyBytes) {
-		return nil, errors.New("body is not a valid JSON")
+		return nil, nil, errors.New("body is not a valid JSON")
 	}
 
+	return bodyBytes, res.Header, nil
+}
+
+func extractValues(stores []configs.FeedStore, bodyBytes []byte) map[string]gjson.Result {
 	var paths []string
-	for _, s := range feed.Store {
+	for _, s := range stores {
 		paths = append(paths, s.Path)
 	}
 
 	jsonResults := gjson.GetManyBytes(bodyBytes, paths...)
 
 	results := make(map[string]gjson.Result, 0)
-	for i, s := range feed.Store {
+	for i, s := range stores {
 		results[s.Name] = jsonResults[i]
 	}
 
-	return results, nil
+	return results
 }
 
 func (s *Server) updateNextRun(feed *configs.FeedConfig) error {
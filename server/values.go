@@ -5,27 +5,57 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 )
 
+// valuesPayload is sent by value events and the values api with
+// `?updated=1`, updated holds when each value was last set.
+type valuesPayload struct {
+	Values  map[string]map[string]interface{} `json:"values"`
+	Updated map[string]map[string]time.Time   `json:"updated"`
+}
+
+func (s *Server) valuesJson(withUpdated bool) ([]byte, error) {
+	values, err := s.Store.GetValues()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get data: %w", err)
+	}
+
+	var data interface{} = values
+	if withUpdated {
+		updated, err := s.Store.GetUpdated()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get updated times: %w", err)
+		}
+
+		data = valuesPayload{
+			Values:  values,
+			Updated: updated,
+		}
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal data: %w", err)
+	}
+
+	return jsonData, nil
+}
+
+// ValuesHandler returns values by feed and store name,
+// `?updated=1` also includes when each value was last updated.
 func (s *Server) ValuesHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.NotFound(w, r)
 		return
 	}
 
-	data, err := s.Store.GetValues()
+	jsonData, err := s.valuesJson(r.URL.Query().Get("updated") != "")
 	if err != nil {
-		log.Println(fmt.Errorf("unable to get data: %w\n", err))
+		log.Println(err)
 		http.Error(w, "unable to get data", 500)
 		return
 	}
 
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		log.Println(fmt.Errorf("unable to marshal data: %w\n", err))
-		http.Error(w, "unable to marshal data", 500)
-		return
-	}
-
 	w.Write(jsonData)
 }
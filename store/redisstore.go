@@ -2,9 +2,10 @@ package store
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
-	"log"
 	"fmt"
+	"log"
 	"os"
 	"strings"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/tidwall/gjson"
 
 	"github.com/miniscruff/dashy/configs"
+	"github.com/miniscruff/dashy/metrics"
 )
 
 func valueKey(name, value string) string {
@@ -23,34 +25,50 @@ func timeKey(name string) string {
 	return fmt.Sprintf("next-update:%v", name)
 }
 
+func alertKey(name string) string {
+	return fmt.Sprintf("alert:%v", name)
+}
+
+func statusKey(name string) string {
+	return fmt.Sprintf("status:%v", name)
+}
+
+func updatedKey(name string) string {
+	return fmt.Sprintf("updated:%v", name)
+}
+
+func cacheKey(name string) string {
+	return fmt.Sprintf("cache:%v", name)
+}
+
 type RedisStore struct {
 	config *configs.Config
 	ctx    context.Context
 	client *redis.Client
 }
 
-func NewRedisStore(config *configs.Config) (*RedisStore, error) {
-	var (
-		opts *redis.Options
-		err  error
-	)
+// RedisOptions builds client options from the redis url or address env vars
+func RedisOptions(env configs.EnvConfig) (*redis.Options, error) {
+	if env.RedisUrl != "" {
+		return redis.ParseURL(env.RedisUrl)
+	}
 
-	if config.Env.RedisUrl != "" {
-		opts, err = redis.ParseURL(config.Env.RedisUrl)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		if config.Env.RedisAddress == "" {
-			return nil, errors.New("missing REDIS_ADDRESS env var")
-		}
+	if env.RedisAddress == "" {
+		return nil, errors.New("missing REDIS_ADDRESS env var")
+	}
 
-		opts = &redis.Options{
-			Addr:     config.Env.RedisAddress,
-			Username: config.Env.RedisUsername,
-			Password: config.Env.RedisPassword,
-			DB:       config.Env.RedisDatabase,
-		}
+	return &redis.Options{
+		Addr:     env.RedisAddress,
+		Username: env.RedisUsername,
+		Password: env.RedisPassword,
+		DB:       env.RedisDatabase,
+	}, nil
+}
+
+func NewRedisStore(config *configs.Config) (*RedisStore, error) {
+	opts, err := RedisOptions(config.Env)
+	if err != nil {
+		return nil, err
 	}
 
 	client := redis.NewClient(opts)
@@ -70,6 +88,8 @@ func (s *RedisStore) StringOrVar(value string) string {
 }
 
 func (s *RedisStore) GetNextRun(feed *configs.FeedConfig) (time.Time, error) {
+	defer metrics.ObserveStore("get_next_run")()
+
 	timeStr, err := s.client.Get(s.ctx, timeKey(feed.Name)).Result()
 	if err == redis.Nil || err != nil {
 		return time.Time{}, nil
@@ -79,29 +99,30 @@ func (s *RedisStore) GetNextRun(feed *configs.FeedConfig) (time.Time, error) {
 }
 
 func (s *RedisStore) SetNextRun(feed *configs.FeedConfig, nextRun time.Time) error {
+	defer metrics.ObserveStore("set_next_run")()
+
 	timeFormatted := nextRun.Format(timeFormat)
 	_, err := s.client.Set(s.ctx, timeKey(feed.Name), timeFormatted, 0).Result()
 	return err
 }
 
 func (s *RedisStore) GetValues() (map[string]map[string]interface{}, error) {
+	defer metrics.ObserveStore("get_values")()
+
 	pipe := s.client.Pipeline()
 
 	var keys []string
 	for _, feed := range s.config.Feeds {
-		for _, store := range feed.Store {
-			key := valueKey(feed.Name, store.Name)
-			if store.IsArray {
-				pipe.LRange(s.ctx, key, 0, -1)
-			} else {
-				pipe.Get(s.ctx, key)
-			}
+		for _, store := range feed.AllStores() {
+			pipe.Get(s.ctx, valueKey(feed.Name, store.Name))
 			keys = append(keys, feed.Name+"|"+store.Name)
 		}
 	}
 
+	// errors replied for a single key are handled per command below
+	var replyErr redis.Error
 	cmds, err := pipe.Exec(s.ctx)
-	if err != nil {
+	if err != nil && err != redis.Nil && !errors.As(err, &replyErr) {
 		log.Println(fmt.Errorf("unable to get data: %w\n", err))
 		return nil, err
 	}
@@ -113,36 +134,222 @@ func (s *RedisStore) GetValues() (map[string]map[string]interface{}, error) {
 			data[split[0]] = make(map[string]interface{}, 0)
 		}
 
-		switch c := cmd.(type) {
-		case *redis.StringCmd:
-			data[split[0]][split[1]] = c.Val()
-		case *redis.StringSliceCmd:
-			data[split[0]][split[1]] = c.Val()
+		c := cmd.(*redis.StringCmd)
+		switch {
+		case c.Err() == redis.Nil:
+			data[split[0]][split[1]] = nil
+		case c.Err() != nil:
+			data[split[0]][split[1]] = s.legacyList(split[0], split[1], c.Err())
 		default:
-			fmt.Printf("unknown type: %v\n", c)
-			data[split[0]][split[1]] = c.String()
+			data[split[0]][split[1]] = decodeValue(c.Val())
 		}
 	}
 
 	return data, nil
 }
 
+// TouchUpdated marks every value of a feed as updated now without
+// changing them, such as when a response was not modified.
+func (s *RedisStore) TouchUpdated(feed *configs.FeedConfig) error {
+	defer metrics.ObserveStore("touch_updated")()
+
+	updatedAt := time.Now().UTC().Format(time.RFC3339)
+	fields := make([]interface{}, 0, len(feed.Store)*2)
+	for _, store := range feed.Store {
+		fields = append(fields, store.Name, updatedAt)
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return s.client.HSet(s.ctx, updatedKey(feed.Name), fields...).Err()
+}
+
+// legacyList reads arrays stored as lists before all values were stored
+// as json, they are replaced the next time the feed is updated.
+func (s *RedisStore) legacyList(feed, store string, getErr error) interface{} {
+	items, err := s.client.LRange(s.ctx, valueKey(feed, store), 0, -1).Result()
+	if err != nil {
+		log.Println(fmt.Errorf("unable to get value '%v.%v' (%v) or read it as a list: %w", feed, store, getErr, err))
+		return nil
+	}
+
+	values := make([]interface{}, len(items))
+	for i, item := range items {
+		values[i] = decodeValue(item)
+	}
+	return values
+}
+
 func (s *RedisStore) SetValues(feed *configs.FeedConfig, values map[string]gjson.Result) error {
+	defer metrics.ObserveStore("set_values")()
+
 	pipe := s.client.Pipeline()
 
+	updatedAt := time.Now().UTC().Format(time.RFC3339)
 	for k, result := range values {
-		key := valueKey(feed.Name, k)
-		if result.IsArray() {
-			for _, v := range result.Array() {
-				pipe.RPush(s.ctx, key, v.Value())
+		pipe.HSet(s.ctx, updatedKey(feed.Name), k, updatedAt)
+		// set replaces any type so arrays, objects and scalars share one key
+		pipe.Set(s.ctx, valueKey(feed.Name, k), encodeValue(result), 0)
+	}
+
+	_, err := pipe.Exec(s.ctx)
+	return err
+}
+
+// GetUpdated returns when each value was last set, by feed and store name.
+// Values that have never been set are not included.
+func (s *RedisStore) GetUpdated() (map[string]map[string]time.Time, error) {
+	defer metrics.ObserveStore("get_updated")()
+
+	pipe := s.client.Pipeline()
+	for _, feed := range s.config.Feeds {
+		pipe.HGetAll(s.ctx, updatedKey(feed.Name))
+	}
+
+	cmds, err := pipe.Exec(s.ctx)
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	updated := make(map[string]map[string]time.Time, len(cmds))
+	for i, cmd := range cmds {
+		fields := cmd.(*redis.StringStringMapCmd).Val()
+		if len(fields) == 0 {
+			continue
+		}
+
+		times := make(map[string]time.Time, len(fields))
+		for name, timeStr := range fields {
+			t, err := time.Parse(time.RFC3339, timeStr)
+			if err != nil {
+				return nil, err
 			}
-			start := -int64(len(result.Array()))
-			pipe.LTrim(s.ctx, key, start, -1)
-		} else {
-			pipe.Set(s.ctx, key, result.Value(), 0)
+			times[name] = t
 		}
+		updated[s.config.Feeds[i].Name] = times
 	}
 
-	_, err := pipe.Exec(s.ctx)
+	return updated, nil
+}
+
+// encodeValue stores values as raw json so types survive the round trip
+func encodeValue(result gjson.Result) string {
+	if !result.Exists() {
+		return "null"
+	}
+	return result.Raw
+}
+
+// decodeValue parses a stored json value, values written before
+// values were stored as json are returned as plain strings.
+func decodeValue(raw string) interface{} {
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return raw
+	}
+	return value
+}
+
+func (s *RedisStore) GetAlertState(name string) (AlertState, error) {
+	defer metrics.ObserveStore("get_alert_state")()
+
+	var state AlertState
+
+	stateStr, err := s.client.Get(s.ctx, alertKey(name)).Result()
+	if err == redis.Nil {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+
+	err = json.Unmarshal([]byte(stateStr), &state)
+	return state, err
+}
+
+func (s *RedisStore) SetAlertState(name string, state AlertState) error {
+	defer metrics.ObserveStore("set_alert_state")()
+
+	stateBytes, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Set(s.ctx, alertKey(name), string(stateBytes), 0).Result()
+	return err
+}
+
+func (s *RedisStore) GetResponseCache(feed *configs.FeedConfig) (ResponseCache, error) {
+	defer metrics.ObserveStore("get_response_cache")()
+
+	var cache ResponseCache
+
+	cacheStr, err := s.client.Get(s.ctx, cacheKey(feed.Name)).Result()
+	if err == redis.Nil {
+		return cache, nil
+	}
+	if err != nil {
+		return cache, err
+	}
+
+	err = json.Unmarshal([]byte(cacheStr), &cache)
+	return cache, err
+}
+
+func (s *RedisStore) SetResponseCache(feed *configs.FeedConfig, cache ResponseCache) error {
+	defer metrics.ObserveStore("set_response_cache")()
+
+	cacheBytes, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Set(s.ctx, cacheKey(feed.Name), string(cacheBytes), 0).Result()
+	return err
+}
+
+func (s *RedisStore) GetFeedStatuses() (map[string]FeedStatus, error) {
+	defer metrics.ObserveStore("get_feed_statuses")()
+
+	pipe := s.client.Pipeline()
+	for _, feed := range s.config.Feeds {
+		pipe.Get(s.ctx, statusKey(feed.Name))
+	}
+
+	cmds, err := pipe.Exec(s.ctx)
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	statuses := make(map[string]FeedStatus, len(cmds))
+	for i, cmd := range cmds {
+		statusStr, err := cmd.(*redis.StringCmd).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var status FeedStatus
+		if err := json.Unmarshal([]byte(statusStr), &status); err != nil {
+			return nil, err
+		}
+		statuses[s.config.Feeds[i].Name] = status
+	}
+
+	return statuses, nil
+}
+
+func (s *RedisStore) SetFeedStatus(feed *configs.FeedConfig, status FeedStatus) error {
+	defer metrics.ObserveStore("set_feed_status")()
+
+	statusBytes, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Set(s.ctx, statusKey(feed.Name), string(statusBytes), 0).Result()
 	return err
 }
@@ -15,4 +15,37 @@ type Store interface {
 	SetNextRun(feed *configs.FeedConfig, nextRun time.Time) error
 	GetValues() (map[string]map[string]interface{}, error)
 	SetValues(feed *configs.FeedConfig, values map[string]gjson.Result) error
+	GetUpdated() (map[string]map[string]time.Time, error)
+	TouchUpdated(feed *configs.FeedConfig) error
+	GetAlertState(name string) (AlertState, error)
+	SetAlertState(name string, state AlertState) error
+	GetResponseCache(feed *configs.FeedConfig) (ResponseCache, error)
+	SetResponseCache(feed *configs.FeedConfig, cache ResponseCache) error
+	GetFeedStatuses() (map[string]FeedStatus, error)
+	SetFeedStatus(feed *configs.FeedConfig, status FeedStatus) error
+}
+
+// FeedStatus is the result of the last update of a feed
+type FeedStatus struct {
+	Healthy   bool      `json:"healthy"`
+	Stale     bool      `json:"stale"`
+	Error     string    `json:"error,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// AlertState is the last known state of an alert, used to dedupe messages
+type AlertState struct {
+	Firing   bool      `json:"firing"`
+	LastSent time.Time `json:"lastSent"`
+	// Failed are the notifiers the last message could not be sent to,
+	// they are retried on the next check without resending to the others.
+	Failed []string `json:"failed,omitempty"`
+}
+
+// ResponseCache holds the validators of the last response of a feed
+// to send conditional requests.
+type ResponseCache struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
 }